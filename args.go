@@ -17,6 +17,8 @@ package cobra
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"unsafe"
 )
 
 type PositionalArgs func(cmd *Command, args []string) error
@@ -33,7 +35,7 @@ func legacyArgs(cmd *Command, args []string) error {
 
 	// root command with subcommands, do subcommand checking.
 	if !cmd.HasParent() && len(args) > 0 {
-		return fmt.Errorf("unknown command %q for %q%s", args[0], cmd.CommandPath(), cmd.findSuggestions(args[0]))
+		return cmd.argError(ErrorKindUnknownCommand, Translate("unknown command %q for %q%s"), args[0])
 	}
 	return nil
 }
@@ -41,7 +43,11 @@ func legacyArgs(cmd *Command, args []string) error {
 // NoArgs returns an error if any args are included.
 func NoArgs(cmd *Command, args []string) error {
 	if len(args) > 0 {
-		return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+		return &CommandError{
+			Kind:     ErrorKindUnknownCommand,
+			Argument: args[0],
+			Err:      fmt.Errorf(Translate("unknown command %q for %q"), args[0], cmd.CommandPath()),
+		}
 	}
 	return nil
 }
@@ -58,7 +64,7 @@ func OnlyValidArgs(cmd *Command, args []string) error {
 		}
 		for _, v := range args {
 			if !stringInSlice(v, validArgs) {
-				return fmt.Errorf("invalid argument %q for %q%s", v, cmd.CommandPath(), cmd.findSuggestions(args[0]))
+				return cmd.argError(ErrorKindInvalidArgument, Translate("invalid argument %q for %q%s"), v)
 			}
 		}
 	}
@@ -72,54 +78,74 @@ func ArbitraryArgs(cmd *Command, args []string) error {
 
 // MinimumNArgs returns an error if there is not at least N args.
 func MinimumNArgs(n int) PositionalArgs {
-	return func(cmd *Command, args []string) error {
+	return recordArgs(func(cmd *Command, args []string) error {
 		if len(args) < n {
-			return fmt.Errorf("requires at least %d arg(s), only received %d", n, len(args))
+			return argsCountError(Translate("requires at least %d arg(s), only received %d"), n, len(args))
 		}
 		return nil
-	}
+	}, argsInfo{name: "MinimumNArgs", min: n, max: -1, counted: true})
 }
 
 // MaximumNArgs returns an error if there are more than N args.
 func MaximumNArgs(n int) PositionalArgs {
-	return func(cmd *Command, args []string) error {
+	return recordArgs(func(cmd *Command, args []string) error {
 		if len(args) > n {
-			return fmt.Errorf("accepts at most %d arg(s), received %d", n, len(args))
+			return argsCountError(Translate("accepts at most %d arg(s), received %d"), n, len(args))
 		}
 		return nil
-	}
+	}, argsInfo{name: "MaximumNArgs", min: 0, max: n, counted: true})
 }
 
 // ExactArgs returns an error if there are not exactly n args.
 func ExactArgs(n int) PositionalArgs {
-	return func(cmd *Command, args []string) error {
+	return recordArgs(func(cmd *Command, args []string) error {
 		if len(args) != n {
-			return fmt.Errorf("accepts %d arg(s), received %d", n, len(args))
+			return argsCountError(Translate("accepts %d arg(s), received %d"), n, len(args))
 		}
 		return nil
-	}
+	}, argsInfo{name: "ExactArgs", min: n, max: n, counted: true})
 }
 
 // RangeArgs returns an error if the number of args is not within the expected range.
 func RangeArgs(min int, max int) PositionalArgs {
-	return func(cmd *Command, args []string) error {
+	return recordArgs(func(cmd *Command, args []string) error {
 		if len(args) < min || len(args) > max {
-			return fmt.Errorf("accepts between %d and %d arg(s), received %d", min, max, len(args))
+			return argsCountError(Translate("accepts between %d and %d arg(s), received %d"), min, max, len(args))
 		}
 		return nil
-	}
+	}, argsInfo{name: "RangeArgs", min: min, max: max, counted: true})
+}
+
+// argsCountError returns the error of the validators of the number of arguments,
+// formatted from format and a.
+func argsCountError(format string, a ...interface{}) error {
+	return &CommandError{Kind: ErrorKindInvalidArgs, Err: fmt.Errorf(format, a...)}
 }
 
 // MatchAll allows combining several PositionalArgs to work in concert.
 func MatchAll(pargs ...PositionalArgs) PositionalArgs {
-	return func(cmd *Command, args []string) error {
+	info := argsInfo{name: "MatchAll", max: -1, counted: true}
+	for _, parg := range pargs {
+		part, ok := lookupArgs(parg)
+		if !ok || !part.counted {
+			info.counted = false
+			break
+		}
+		if part.min > info.min {
+			info.min = part.min
+		}
+		if part.max >= 0 && (info.max < 0 || part.max < info.max) {
+			info.max = part.max
+		}
+	}
+	return recordArgs(func(cmd *Command, args []string) error {
 		for _, parg := range pargs {
 			if err := parg(cmd, args); err != nil {
 				return err
 			}
 		}
 		return nil
-	}
+	}, info)
 }
 
 // ExactValidArgs returns an error if there are not exactly N positional args OR
@@ -129,3 +155,64 @@ func MatchAll(pargs ...PositionalArgs) PositionalArgs {
 func ExactValidArgs(n int) PositionalArgs {
 	return MatchAll(ExactArgs(n), OnlyValidArgs)
 }
+
+// argsInfo describes a validator built by Cobra.
+type argsInfo struct {
+	// name is the name of the function the validator was built by, such as "ExactArgs".
+	name string
+	// min and max are the numbers of arguments accepted by the validator, when counted
+	// is true. max is -1 when the number of arguments is not bounded.
+	min, max int
+	counted  bool
+}
+
+// countString describes the numbers of arguments accepted by the validator.
+func (i argsInfo) countString() string {
+	switch {
+	case i.max < 0:
+		return fmt.Sprintf("%s accepts at least %d argument(s)", i.name, i.min)
+	case i.min == i.max:
+		return fmt.Sprintf("%s accepts %d argument(s)", i.name, i.min)
+	}
+	return fmt.Sprintf("%s accepts between %d and %d argument(s)", i.name, i.min, i.max)
+}
+
+var (
+	argsInfos      = map[unsafe.Pointer]argsInfo{}
+	argsInfosMutex sync.RWMutex
+)
+
+// recordArgs records info about args, a validator built by Cobra, and returns args.
+func recordArgs(args PositionalArgs, info argsInfo) PositionalArgs {
+	argsInfosMutex.Lock()
+	argsInfos[argsIdentity(args)] = info
+	argsInfosMutex.Unlock()
+	return args
+}
+
+// lookupArgs returns the description of args, or false if args was not built by
+// Cobra. args is not called.
+func lookupArgs(args PositionalArgs) (argsInfo, bool) {
+	if args == nil {
+		return argsInfo{}, false
+	}
+	switch argsIdentity(args) {
+	case argsIdentity(NoArgs):
+		return argsInfo{name: "NoArgs", min: 0, max: 0, counted: true}, true
+	case argsIdentity(ArbitraryArgs):
+		return argsInfo{name: "ArbitraryArgs", min: 0, max: -1, counted: true}, true
+	case argsIdentity(OnlyValidArgs):
+		return argsInfo{name: "OnlyValidArgs", min: 0, max: -1, counted: true}, true
+	}
+	argsInfosMutex.RLock()
+	defer argsInfosMutex.RUnlock()
+	info, ok := argsInfos[argsIdentity(args)]
+	return info, ok
+}
+
+// argsIdentity returns the address of the function value args, which is the same
+// for every use of a function such as NoArgs, and distinct for each validator
+// returned by a call to ExactArgs, RangeArgs and the like.
+func argsIdentity(args PositionalArgs) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&args))
+}
@@ -0,0 +1,346 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+// The struct tags read by Bind.
+const (
+	bindTagFlag       = "flag"
+	bindTagShorthand  = "short"
+	bindTagUsage      = "usage"
+	bindTagDefault    = "default"
+	bindTagRequired   = "required"
+	bindTagEnv        = "env"
+	bindTagEnum       = "enum"
+	bindTagPersistent = "persistent"
+	bindTagArg        = "arg"
+)
+
+// fieldBinding is a struct field bound to a flag or a positional argument.
+type fieldBinding struct {
+	ptr        interface{}
+	name       string
+	flag       string
+	persistent bool
+	env        string
+	arg        int
+	required   bool
+	// def is the default value of the field, which it is reset to before each execution.
+	def reflect.Value
+	// enum are the values the flag is restricted to.
+	enum []string
+}
+
+// Bind registers the flags and positional arguments described by the tags of the
+// fields of opts, a pointer to a struct, and populates the fields when the command
+// runs, before its hooks. The fields are reset to their default values before each
+// execution. Fields are bound with the following tags:
+//
+//	flag:"name"        binds the field to the flag --name
+//	short:"n"          gives the flag the shorthand -n
+//	usage:"text"       is the usage of the flag
+//	default:"value"    is the default value, instead of the value of the field
+//	required:"true"    marks the flag, or the positional argument, as required
+//	env:"VAR"          sets the flag from the environment variable VAR if it is not set
+//	enum:"a,b,c"       restricts the values of the flag, or of each element of a
+//	                   slice flag, which are then completed
+//	persistent:"true"  registers the flag on PersistentFlags instead of Flags
+//	arg:"0"            binds the field to the positional argument at this index; a
+//	                   []string field receives this argument and all the following
+//
+// Fields of embedded structs are bound too. Flags can be strings, bools, integers,
+// floats, durations, string and int slices, string maps or implement pflag.Value.
+func (c *Command) Bind(opts interface{}) error {
+	v := reflect.ValueOf(opts)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind %T, a pointer to a struct is required", opts)
+	}
+	return c.bindStruct(v.Elem())
+}
+
+// bindStruct binds the tagged fields of the struct v.
+func (c *Command) bindStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := c.bindStruct(v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		name, isFlag := field.Tag.Lookup(bindTagFlag)
+		index, isArg := field.Tag.Lookup(bindTagArg)
+		if (!isFlag && !isArg) || name == "-" {
+			continue
+		}
+		if isFlag && name == "" {
+			return fmt.Errorf("missing flag name of field %s", field.Name)
+		}
+		if field.PkgPath != "" {
+			return fmt.Errorf("cannot bind the unexported field %s", field.Name)
+		}
+
+		b := &fieldBinding{
+			ptr:        v.Field(i).Addr().Interface(),
+			name:       name,
+			flag:       name,
+			persistent: field.Tag.Get(bindTagPersistent) == "true",
+			env:        field.Tag.Get(bindTagEnv),
+			arg:        -1,
+			required:   field.Tag.Get(bindTagRequired) == "true",
+		}
+		if isArg {
+			arg, err := strconv.Atoi(index)
+			if err != nil || arg < 0 {
+				return fmt.Errorf("invalid argument index %q of field %s", index, field.Name)
+			}
+			b.arg, b.flag = arg, ""
+			if b.name == "" {
+				b.name = strings.ToLower(field.Name)
+			}
+		} else if err := c.bindFlag(b, field); err != nil {
+			return err
+		}
+		b.def = copyBoundValue(v.Field(i))
+		c.bindings = append(c.bindings, b)
+	}
+	return nil
+}
+
+// bindFlag registers the flag of b, described by the tags of field.
+func (c *Command) bindFlag(b *fieldBinding, field reflect.StructField) error {
+	usage := field.Tag.Get(bindTagUsage)
+	shorthand := field.Tag.Get(bindTagShorthand)
+	if def, ok := field.Tag.Lookup(bindTagDefault); ok {
+		if err := setBoundValue(b.ptr, def); err != nil {
+			return fmt.Errorf("invalid default value of field %s: %w", field.Name, err)
+		}
+	}
+
+	flags := c.Flags()
+	if b.persistent {
+		flags = c.PersistentFlags()
+	}
+	if err := addBoundFlag(flags, b.ptr, b.flag, shorthand, usage); err != nil {
+		return fmt.Errorf("cannot bind field %s: %w", field.Name, err)
+	}
+
+	if enum := field.Tag.Get(bindTagEnum); enum != "" {
+		b.enum = strings.Split(enum, ",")
+		f := flags.Lookup(b.flag)
+		f.Value = &enumValue{Value: f.Value, values: b.enum}
+		if err := c.RegisterFlagCompletionFunc(b.flag, FixedCompletions(b.enum, ShellCompDirectiveNoFileComp)); err != nil {
+			return err
+		}
+	}
+	if b.required {
+		return MarkFlagRequired(flags, b.flag)
+	}
+	return nil
+}
+
+// addBoundFlag adds to flags the flag name storing its value in ptr, whose current
+// value is the default of the flag.
+func addBoundFlag(flags *flag.FlagSet, ptr interface{}, name, shorthand, usage string) error {
+	switch p := ptr.(type) {
+	case flag.Value:
+		flags.VarP(p, name, shorthand, usage)
+	case *string:
+		flags.StringVarP(p, name, shorthand, *p, usage)
+	case *bool:
+		flags.BoolVarP(p, name, shorthand, *p, usage)
+	case *int:
+		flags.IntVarP(p, name, shorthand, *p, usage)
+	case *int32:
+		flags.Int32VarP(p, name, shorthand, *p, usage)
+	case *int64:
+		flags.Int64VarP(p, name, shorthand, *p, usage)
+	case *uint:
+		flags.UintVarP(p, name, shorthand, *p, usage)
+	case *uint64:
+		flags.Uint64VarP(p, name, shorthand, *p, usage)
+	case *float32:
+		flags.Float32VarP(p, name, shorthand, *p, usage)
+	case *float64:
+		flags.Float64VarP(p, name, shorthand, *p, usage)
+	case *time.Duration:
+		flags.DurationVarP(p, name, shorthand, *p, usage)
+	case *[]string:
+		flags.StringSliceVarP(p, name, shorthand, *p, usage)
+	case *[]int:
+		flags.IntSliceVarP(p, name, shorthand, *p, usage)
+	case *map[string]string:
+		flags.StringToStringVarP(p, name, shorthand, *p, usage)
+	default:
+		return fmt.Errorf("unsupported type %s", reflect.TypeOf(ptr).Elem())
+	}
+	return nil
+}
+
+// setBoundValue parses s into ptr, as if given to a flag of its type.
+func setBoundValue(ptr interface{}, s string) error {
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	if err := addBoundFlag(flags, ptr, "value", "", ""); err != nil {
+		return err
+	}
+	return flags.Lookup("value").Value.Set(s)
+}
+
+// enumValue is a flag value restricted to a set of values.
+type enumValue struct {
+	flag.Value
+	values []string
+}
+
+// Set sets the value if it is one of the allowed values. The value of a slice flag
+// is a comma-separated list, each element of which must be allowed.
+func (v *enumValue) Set(s string) error {
+	elements := []string{s}
+	if t := v.Value.Type(); t == "stringSlice" || t == "intSlice" {
+		var err error
+		if elements, err = readCSV(s); err != nil {
+			return err
+		}
+	}
+	for _, element := range elements {
+		if !stringInSlice(element, v.values) {
+			return fmt.Errorf(Translate("must be one of: %s"), strings.Join(v.values, "|"))
+		}
+	}
+	return v.Value.Set(s)
+}
+
+// readCSV splits s as the string and int slice flags do.
+func readCSV(s string) ([]string, error) {
+	if s == "" {
+		return []string{}, nil
+	}
+	return csv.NewReader(strings.NewReader(s)).Read()
+}
+
+// copyBoundValue returns a copy of v, which does not share the elements of a slice or a map.
+func copyBoundValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	switch {
+	case v.Kind() == reflect.Slice && !v.IsNil():
+		c.Set(reflect.AppendSlice(reflect.MakeSlice(v.Type(), 0, v.Len()), v))
+	case v.Kind() == reflect.Map && !v.IsNil():
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+	default:
+		c.Set(v)
+	}
+	return c
+}
+
+// resetBindings restores the fields bound by Bind on c and its parents to their
+// default values and marks their flags as not set, so that a command executed
+// more than once does not keep the values of the previous execution.
+func (c *Command) resetBindings() {
+	for p := c; p != nil; p = p.parent {
+		for _, b := range p.bindings {
+			reflect.ValueOf(b.ptr).Elem().Set(copyBoundValue(b.def))
+			if b.flag == "" {
+				continue
+			}
+			flags := p.Flags()
+			if b.persistent {
+				flags = p.PersistentFlags()
+			}
+			f := flags.Lookup(b.flag)
+			if f == nil {
+				continue
+			}
+			// A new value forgets that the flag was set, which matters to slices
+			// and maps, appended to by a flag given more than once.
+			values := flag.NewFlagSet("", flag.ContinueOnError)
+			if err := addBoundFlag(values, b.ptr, "value", "", ""); err != nil {
+				continue
+			}
+			f.Value = values.Lookup("value").Value
+			if len(b.enum) > 0 {
+				f.Value = &enumValue{Value: f.Value, values: b.enum}
+			}
+			f.Changed = false
+		}
+	}
+}
+
+// applyBindings populates the fields bound by Bind on c and its parents: the
+// flags not set on the command line are set from their environment variables,
+// and the positional arguments are stored in their fields.
+func (c *Command) applyBindings(args []string) error {
+	for p := c; p != nil; p = p.parent {
+		for _, b := range p.bindings {
+			if b.env == "" || (p != c && !b.persistent) {
+				continue
+			}
+			f := c.Flags().Lookup(b.flag)
+			value, ok := os.LookupEnv(b.env)
+			if f == nil || f.Changed || !ok {
+				continue
+			}
+			if err := c.Flags().Set(b.flag, value); err != nil {
+				return &CommandError{
+					Kind:  ErrorKindInvalidFlagValue,
+					Flags: []string{b.flag},
+					Err:   fmt.Errorf(Translate("invalid value %q of %s for flag --%s: %w"), value, b.env, b.flag, err),
+				}
+			}
+		}
+	}
+
+	for _, b := range c.bindings {
+		if b.arg < 0 {
+			continue
+		}
+		if b.arg >= len(args) {
+			if b.required {
+				return &CommandError{
+					Kind: ErrorKindInvalidArgs,
+					Err:  fmt.Errorf(Translate("missing required argument %q for %q"), b.name, c.CommandPath()),
+				}
+			}
+			continue
+		}
+		if rest, ok := b.ptr.(*[]string); ok {
+			*rest = append([]string{}, args[b.arg:]...)
+			continue
+		}
+		if err := setBoundValue(b.ptr, args[b.arg]); err != nil {
+			return &CommandError{
+				Kind:     ErrorKindInvalidArgument,
+				Argument: args[b.arg],
+				Err:      fmt.Errorf(Translate("invalid argument %q for %q: %w"), args[b.arg], b.name, err),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindCommonOptions struct {
+	Verbose bool `flag:"verbose" short:"v" usage:"verbose output" persistent:"true" env:"BIND_TEST_VERBOSE"`
+}
+
+type bindOptions struct {
+	bindCommonOptions
+	Name    string        `flag:"name" short:"n" usage:"the name" required:"true" env:"BIND_TEST_NAME"`
+	Count   int           `flag:"count" usage:"how many" default:"3"`
+	Timeout time.Duration `flag:"timeout" usage:"how long"`
+	Format  string        `flag:"format" usage:"output format" default:"json" enum:"json,yaml"`
+	Tags    []string      `flag:"tag" usage:"tags"`
+	Kind    string        `arg:"0" required:"true"`
+	Index   int           `arg:"1"`
+	Rest    []string      `arg:"2"`
+	Ignored string
+}
+
+func bindTestCmd(t *testing.T, opts *bindOptions) *Command {
+	c := &Command{Use: "c <kind> [index] [rest...]", Run: emptyRun}
+	if err := c.Bind(opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestBind(t *testing.T) {
+	opts := &bindOptions{}
+	c := bindTestCmd(t, opts)
+
+	_, err := executeCommand(c, "user", "2", "a", "b", "--name", "alice", "-v", "--timeout", "1m", "--tag", "x,y")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := bindOptions{
+		bindCommonOptions: bindCommonOptions{Verbose: true},
+		Name:              "alice",
+		Count:             3,
+		Timeout:           time.Minute,
+		Format:            "json",
+		Tags:              []string{"x", "y"},
+		Kind:              "user",
+		Index:             2,
+		Rest:              []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(*opts, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, *opts)
+	}
+}
+
+func TestBindFlagDefinitions(t *testing.T) {
+	c := bindTestCmd(t, &bindOptions{})
+
+	f := c.Flags().Lookup("name")
+	if f == nil || f.Shorthand != "n" || f.Usage != "the name" {
+		t.Fatalf("Expected the name flag, got %+v", f)
+	}
+	if f.Annotations[BashCompOneRequiredFlag] == nil {
+		t.Errorf("Expected the name flag to be required")
+	}
+	if f := c.Flags().Lookup("count"); f == nil || f.DefValue != "3" {
+		t.Errorf("Expected the count flag to default to 3, got %+v", f)
+	}
+	if c.PersistentFlags().Lookup("verbose") == nil {
+		t.Errorf("Expected the verbose flag to be persistent")
+	}
+	if c.Flags().Lookup("ignored") != nil || c.Flags().Lookup("kind") != nil {
+		t.Errorf("Expected untagged fields and arguments not to be flags")
+	}
+}
+
+func TestBindRequired(t *testing.T) {
+	_, err := executeCommand(bindTestCmd(t, &bindOptions{}), "user")
+	if err == nil || err.Error() != `required flag(s) "name" not set` {
+		t.Errorf("Expected the required flag error, got %v", err)
+	}
+
+	_, err = executeCommand(bindTestCmd(t, &bindOptions{}), "--name", "alice")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Kind != ErrorKindInvalidArgs {
+		t.Fatalf("Expected a missing argument error, got %v", err)
+	}
+	checkStringContains(t, err.Error(), `missing required argument "kind"`)
+}
+
+func TestBindEnum(t *testing.T) {
+	_, err := executeCommand(bindTestCmd(t, &bindOptions{}), "user", "--name", "alice", "--format", "xml")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringContains(t, err.Error(), "must be one of: json|yaml")
+
+	output, err := executeCommand(bindTestCmd(t, &bindOptions{}), ShellCompRequestCmd, "user", "--format", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "json\nyaml\n:4\n")
+}
+
+func TestBindEnumSlice(t *testing.T) {
+	opts := &struct {
+		Formats []string `flag:"format" enum:"json,yaml"`
+	}{}
+	c := &Command{Use: "c", Run: emptyRun}
+	if err := c.Bind(opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := executeCommand(c, "--format", "json,yaml", "--format", "json"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(opts.Formats, []string{"json", "yaml", "json"}) {
+		t.Errorf("Expected the formats to be set, got %q", opts.Formats)
+	}
+
+	_, err := executeCommand(c, "--format", "json,xml")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	checkStringContains(t, err.Error(), "must be one of: json|yaml")
+}
+
+func TestBindResetBetweenExecutions(t *testing.T) {
+	opts := &bindOptions{}
+	c := bindTestCmd(t, opts)
+
+	if _, err := executeCommand(c, "user", "2", "a", "--name", "alice", "--count", "5", "--tag", "x"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(c, "group", "--name", "bob", "--tag", "y"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := bindOptions{
+		Name:   "bob",
+		Count:  3,
+		Format: "json",
+		Tags:   []string{"y"},
+		Kind:   "group",
+	}
+	if !reflect.DeepEqual(*opts, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, *opts)
+	}
+
+	_, err := executeCommand(c, "group")
+	if err == nil {
+		t.Fatal("Expected the required flag to be missing again")
+	}
+	checkStringContains(t, err.Error(), `required flag(s) "name" not set`)
+}
+
+func TestBindEnv(t *testing.T) {
+	os.Setenv("BIND_TEST_NAME", "bob")
+	defer os.Unsetenv("BIND_TEST_NAME")
+	os.Setenv("BIND_TEST_VERBOSE", "true")
+	defer os.Unsetenv("BIND_TEST_VERBOSE")
+
+	rootOpts := &bindCommonOptions{}
+	rootCmd := &Command{Use: "root"}
+	if err := rootCmd.Bind(rootOpts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c := &Command{Use: "c", Run: emptyRun}
+	type childOptions struct {
+		Name string `flag:"name" required:"true" env:"BIND_TEST_NAME"`
+	}
+	childOpts := &childOptions{}
+	if err := c.Bind(childOpts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rootCmd.AddCommand(c)
+
+	if _, err := executeCommand(rootCmd, "c"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if childOpts.Name != "bob" || !rootOpts.Verbose {
+		t.Errorf("Expected the flags to be set from the environment, got %+v and %+v", childOpts, rootOpts)
+	}
+
+	if _, err := executeCommand(rootCmd, "c", "--name", "carol"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if childOpts.Name != "carol" {
+		t.Errorf("Expected the command line to take precedence, got %q", childOpts.Name)
+	}
+}
+
+func TestBindInvalidArgument(t *testing.T) {
+	_, err := executeCommand(bindTestCmd(t, &bindOptions{}), "user", "two", "--name", "alice")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Kind != ErrorKindInvalidArgument || cmdErr.Argument != "two" {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+	checkStringContains(t, err.Error(), `invalid argument "two" for "index"`)
+}
+
+func TestBindErrors(t *testing.T) {
+	var notStruct string
+	tests := []struct {
+		opts     interface{}
+		expected string
+	}{
+		{opts: notStruct, expected: "a pointer to a struct is required"},
+		{opts: &notStruct, expected: "a pointer to a struct is required"},
+		{opts: &struct {
+			Ch chan int `flag:"ch"`
+		}{}, expected: "unsupported type chan int"},
+		{opts: &struct {
+			N int `flag:"n" default:"x"`
+		}{}, expected: "invalid default value of field N"},
+		{opts: &struct {
+			N int `arg:"first"`
+		}{}, expected: "invalid argument index"},
+		{opts: &struct {
+			n int `flag:"n"`
+		}{}, expected: "unexported field n"},
+	}
+	for _, tt := range tests {
+		err := (&Command{Use: "c"}).Bind(tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+		}
+	}
+}
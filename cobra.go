@@ -27,14 +27,20 @@ import (
 	"text/template"
 	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
 var templateFuncs = template.FuncMap{
+	"translate":               Translate,
 	"trim":                    strings.TrimSpace,
 	"trimRightSpace":          trimRightSpace,
 	"trimTrailingWhitespaces": trimRightSpace,
 	"appendIfNotPresent":      appendIfNotPresent,
 	"rpad":                    rpad,
+	"wrap":                    wrap,
+	"wrapHanging":             wrapHanging,
+	"indent":                  indent,
+	"flagUsages":              FormatFlagUsages,
 	"gt":                      Gt,
 	"eq":                      Eq,
 }
@@ -43,10 +49,12 @@ var initializers []func()
 var finalizers []func()
 
 const (
-	defaultPrefixMatching   = false
-	defaultCommandSorting   = true
-	defaultCaseInsensitive  = false
-	defaultTraverseRunHooks = false
+	defaultPrefixMatching     = false
+	defaultFlagPrefixMatching = false
+	defaultCommandSorting     = true
+	defaultCaseInsensitive    = false
+	defaultTraverseRunHooks   = false
+	defaultHelpWrapping       = false
 )
 
 // EnablePrefixMatching allows setting automatic prefix matching. Automatic prefix matching can be a dangerous thing
@@ -54,6 +62,11 @@ const (
 // Set this to true to enable it.
 var EnablePrefixMatching = defaultPrefixMatching
 
+// EnableFlagPrefixMatching allows long flags to be abbreviated to any unique prefix of their
+// name, so that --verb may be given for --verbose. An abbreviation matching several flags is an error.
+// Set this to true to enable it.
+var EnableFlagPrefixMatching = defaultFlagPrefixMatching
+
 // EnableCommandSorting controls sorting of the slice of commands, which is turned on by default.
 // To disable sorting, set it to false.
 var EnableCommandSorting = defaultCommandSorting
@@ -65,6 +78,12 @@ var EnableCaseInsensitive = defaultCaseInsensitive
 // By default this is disabled, which means only the first run hook to be found is executed.
 var EnableTraverseRunHooks = defaultTraverseRunHooks
 
+// EnableHelpWrapping wraps the descriptions and flag usages of the default help and
+// usage templates to the width of the terminal. When the output is not a terminal, the width
+// is taken from the COLUMNS environment variable, or defaults to 80 columns.
+// By default this is disabled, which keeps help output unchanged regardless of the terminal.
+var EnableHelpWrapping = defaultHelpWrapping
+
 // MousetrapHelpText enables an information splash screen on Windows
 // if the CLI is started from explorer.exe.
 // To disable the mousetrap, just set this variable to blank string ("").
@@ -176,9 +195,98 @@ func rpad(s string, padding int) string {
 	return fmt.Sprintf(formattedString, s)
 }
 
+// wrap wraps every line of s that is longer than width columns at word boundaries.
+// Continuation lines keep the leading whitespace of the line they were split from.
+// If width is not positive, s is returned unchanged.
+func wrap(width int, s string) string {
+	if width <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if textWidth(line) <= width {
+			continue
+		}
+		text := strings.TrimLeft(line, " \t")
+		lead := line[:len(line)-len(text)]
+		lines[i] = lead + wrapWords(text, textWidth(lead), lead, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapHanging returns prefix followed by s, wrapping s to width columns with
+// its continuation lines indented to the end of prefix.
+// If width is not positive, prefix and s are simply concatenated.
+func wrapHanging(prefix string, width int, s string) string {
+	if width <= 0 {
+		return prefix + s
+	}
+	hang := strings.Repeat(" ", textWidth(prefix))
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if len(hang)+textWidth(line) > width {
+			line = wrapWords(line, len(hang), hang, width)
+		}
+		if i > 0 && line != "" {
+			line = hang + line
+		}
+		lines[i] = line
+	}
+	return prefix + strings.Join(lines, "\n")
+}
+
+// indent prefixes every non-empty line of s with n spaces.
+func indent(n int, s string) string {
+	if n <= 0 {
+		return s
+	}
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapWords lays out the words of text as if it started at column col,
+// breaking onto new lines prefixed with hang whenever width would be exceeded.
+// A word longer than the available space is kept whole on its own line.
+func wrapWords(text string, col int, hang string, width int) string {
+	var sb strings.Builder
+	lineStart := true
+	for _, word := range strings.Fields(text) {
+		wordWidth := textWidth(word)
+		if !lineStart && col+1+wordWidth > width {
+			sb.WriteString("\n")
+			sb.WriteString(hang)
+			col = textWidth(hang)
+			lineStart = true
+		}
+		if !lineStart {
+			sb.WriteString(" ")
+			col++
+		}
+		sb.WriteString(word)
+		col += wordWidth
+		lineStart = false
+	}
+	return sb.String()
+}
+
+// textWidth returns the number of columns s occupies, counting one column per rune
+// and ignoring the escape sequences used for styling.
+func textWidth(s string) int {
+	return utf8.RuneCountInString(stripSGR(s))
+}
+
 // tmpl executes the given template text on data, writing the result to w.
 func tmpl(w io.Writer, text string, data interface{}) error {
 	t := template.New("top")
+	if c, ok := data.(*Command); ok {
+		t.Funcs(c.styleFuncs(w))
+	}
 	t.Funcs(templateFuncs)
 	template.Must(t.Parse(text))
 	return t.Execute(w, data)
@@ -230,7 +338,7 @@ func stringInSlice(a string, list []string) bool {
 // CheckErr prints the msg with the prefix 'Error:' and exits with error code 1. If the msg is nil, it does nothing.
 func CheckErr(msg interface{}) {
 	if msg != nil {
-		fmt.Fprintln(os.Stderr, "Error:", msg)
+		fmt.Fprintln(os.Stderr, Translate("Error:"), msg)
 		os.Exit(1)
 	}
 }
@@ -222,3 +222,146 @@ func TestRpad(t *testing.T) {
 		})
 	}
 }
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		input    string
+		expected string
+	}{
+		{
+			name:     "Wrapping disabled",
+			width:    0,
+			input:    "a long line that would otherwise be wrapped",
+			expected: "a long line that would otherwise be wrapped",
+		},
+		{
+			name:     "Short lines are untouched",
+			width:    20,
+			input:    "keep   spacing\nas is",
+			expected: "keep   spacing\nas is",
+		},
+		{
+			name:     "Long line is wrapped",
+			width:    12,
+			input:    "one two three four five",
+			expected: "one two\nthree four\nfive",
+		},
+		{
+			name:     "Indentation hangs",
+			width:    14,
+			input:    "  one two three four",
+			expected: "  one two\n  three four",
+		},
+		{
+			name:     "Long word is kept whole",
+			width:    5,
+			input:    "abcdefgh ij",
+			expected: "abcdefgh\nij",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := wrap(tt.width, tt.input)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("Expected wrap: %q\nGot: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWrapHanging(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		width    int
+		input    string
+		expected string
+	}{
+		{
+			name:     "Wrapping disabled",
+			prefix:   "  child ",
+			width:    0,
+			input:    "a short description of the child",
+			expected: "  child a short description of the child",
+		},
+		{
+			name:     "Fits on the line",
+			prefix:   "  child ",
+			width:    40,
+			input:    "short description",
+			expected: "  child short description",
+		},
+		{
+			name:     "Continuation lines align with the prefix",
+			prefix:   "  child ",
+			width:    24,
+			input:    "a short description of the child",
+			expected: "  child a short\n        description of\n        the child",
+		},
+		{
+			name:     "Multiple lines",
+			prefix:   "  c ",
+			width:    40,
+			input:    "first\nsecond",
+			expected: "  c first\n    second",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := wrapHanging(tt.prefix, tt.width, tt.input)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("Expected wrapHanging: %q\nGot: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIndent(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		input    string
+		expected string
+	}{
+		{
+			name:     "Every line is indented",
+			n:        2,
+			input:    "one\ntwo",
+			expected: "  one\n  two",
+		},
+		{
+			name:     "Empty lines are left empty",
+			n:        4,
+			input:    "one\n\ntwo",
+			expected: "    one\n\n    two",
+		},
+		{
+			name:     "Zero indentation",
+			n:        0,
+			input:    "one",
+			expected: "one",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := indent(tt.n, tt.input)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("Expected indent: %q\nGot: %q", tt.expected, got)
+			}
+		})
+	}
+}
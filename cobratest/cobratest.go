@@ -0,0 +1,186 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cobratest executes Cobra command trees in tests, capturing their
+// output and comparing it with golden files.
+package cobratest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// cobraEnvVars are the environment variables read by Cobra, besides the ones
+// prefixed by COBRA_ or by the name of the program, and the LC_ ones.
+var cobraEnvVars = []string{"NO_COLOR", "CLICOLOR_FORCE", "COLUMNS", "LINES", "PAGER", "LANG", "BASH_COMP_DEBUG_FILE"}
+
+// programEnvPrefixRegexp matches the characters of the name of a program replaced
+// by '_' in the prefix of its environment variables.
+var programEnvPrefixRegexp = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// Options describe the environment a command tree is executed in.
+type Options struct {
+	// Args are the command line arguments, without the program name.
+	Args []string
+	// Env holds the environment variables set while the command runs. The
+	// variables read by Cobra, such as NO_COLOR, LANG, LC_*, COBRA_* and the
+	// ones prefixed by the name of the program, are cleared before Env is
+	// applied. The previous environment is restored afterwards.
+	Env map[string]string
+	// Stdin is the input of the command.
+	Stdin string
+	// Dir is the working directory of the command. If empty, the working
+	// directory is left unchanged.
+	Dir string
+	// Terminal describes the terminals the streams of the command are
+	// connected to. If nil, the streams are not terminals.
+	Terminal *cobra.TerminalInfo
+}
+
+// Result is the outcome of executing a command tree.
+type Result struct {
+	// Command is the command that was resolved from the arguments.
+	Command *cobra.Command
+	// Stdout and Stderr are the output and error output of the command.
+	Stdout string
+	Stderr string
+	// Err is the error returned by the execution.
+	Err error
+	// ExitCode is the exit status a program would exit with: 0 without an error,
+	// the code of an error implementing ExitCode() int, or 1 for other errors.
+	ExitCode int
+}
+
+// Execute executes root with args and returns the result.
+func Execute(root *cobra.Command, args ...string) *Result {
+	return ExecuteWith(root, Options{Args: args})
+}
+
+// ExecuteWith executes root in the environment described by opts and returns the result.
+// Unlike root.Execute(), it never reads the arguments of the test binary from os.Args.
+// The streams of root are restored afterwards, and its arguments are reset, so that
+// root.Execute() reads them from os.Args again.
+func ExecuteWith(root *cobra.Command, opts Options) *Result {
+	restoreEnv := setEnv(root, opts.Env)
+	defer restoreEnv()
+
+	if opts.Dir != "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return &Result{Err: err, ExitCode: 1}
+		}
+		if err := os.Chdir(opts.Dir); err != nil {
+			return &Result{Err: err, ExitCode: 1}
+		}
+		defer func() { _ = os.Chdir(wd) }()
+	}
+
+	args := opts.Args
+	if args == nil {
+		args = []string{}
+	}
+	terminal := opts.Terminal
+	if terminal == nil {
+		terminal = &cobra.TerminalInfo{}
+	}
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	previous := root.Streams()
+	root.SetArgs(args)
+	root.SetStreams(&cobra.Streams{
+		In:       strings.NewReader(opts.Stdin),
+		Out:      stdout,
+		Err:      stderr,
+		Terminal: terminal,
+	})
+	defer func() {
+		root.SetArgs(nil)
+		root.SetStreams(&cobra.Streams{In: previous.In, Out: previous.Out, Err: previous.Err})
+		root.SetTerminal(previous.Terminal)
+	}()
+
+	cmd, err := root.ExecuteC()
+	return &Result{
+		Command:  cmd,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Err:      err,
+		ExitCode: exitCode(err),
+	}
+}
+
+// exitCode returns the exit status of a program failing with err.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return 1
+}
+
+// setEnv clears the environment variables read by Cobra for the program root,
+// sets the environment variables env and returns a function restoring their
+// previous values.
+func setEnv(root *cobra.Command, env map[string]string) func() {
+	type saved struct {
+		value string
+		set   bool
+	}
+	previous := make(map[string]saved, len(env))
+	save := func(key string) {
+		if _, ok := previous[key]; !ok {
+			old, set := os.LookupEnv(key)
+			previous[key] = saved{old, set}
+		}
+	}
+
+	programPrefix := programEnvPrefixRegexp.ReplaceAllString(strings.ToUpper(root.Root().Name()), "_") + "_"
+	for _, kv := range os.Environ() {
+		key := strings.SplitN(kv, "=", 2)[0]
+		if stringInSlice(key, cobraEnvVars) || strings.HasPrefix(key, "LC_") ||
+			strings.HasPrefix(key, "COBRA_") || strings.HasPrefix(key, programPrefix) {
+			save(key)
+			os.Unsetenv(key)
+		}
+	}
+	for key, value := range env {
+		save(key)
+		os.Setenv(key, value)
+	}
+	return func() {
+		for key, old := range previous {
+			if old.set {
+				os.Setenv(key, old.value)
+			} else {
+				os.Unsetenv(key)
+			}
+		}
+	}
+}
+
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobratest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type exitError struct{ code int }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e exitError) ExitCode() int { return e.code }
+
+func testTree() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "root", Short: "The root command", SilenceUsage: true}
+	echoCmd := &cobra.Command{
+		Use:   "echo",
+		Short: "Echo the arguments",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println(args)
+			cmd.PrintErrln("done")
+			return nil
+		},
+	}
+	echoCmd.Flags().String("format", "text", "output format")
+	_ = echoCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text\tPlain text", "json\tJSON"}, cobra.ShellCompDirectiveNoFileComp))
+	envCmd := &cobra.Command{
+		Use: "env",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Print(os.Getenv("COBRATEST_VALUE"))
+		},
+	}
+	pwdCmd := &cobra.Command{
+		Use: "pwd",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wd, err := os.Getwd()
+			cmd.Print(filepath.Base(wd))
+			return err
+		},
+	}
+	catCmd := &cobra.Command{
+		Use: "cat",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in, err := ioutil.ReadAll(cmd.InOrStdin())
+			cmd.Print(string(in))
+			return err
+		},
+	}
+	failCmd := &cobra.Command{
+		Use: "fail",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("failed: %w", exitError{3})
+		},
+	}
+	rootCmd.AddCommand(echoCmd, envCmd, pwdCmd, catCmd, failCmd)
+	return rootCmd
+}
+
+func TestExecute(t *testing.T) {
+	result := Execute(testTree(), "echo", "a", "b")
+	if result.Err != nil {
+		t.Fatalf("Unexpected error: %v", result.Err)
+	}
+	if result.Command.Name() != "echo" {
+		t.Errorf("Expected the echo command, got %q", result.Command.Name())
+	}
+	if result.Stdout != "[a b]\n" {
+		t.Errorf("Expected %q on stdout, got %q", "[a b]\n", result.Stdout)
+	}
+	if result.Stderr != "done\n" {
+		t.Errorf("Expected %q on stderr, got %q", "done\n", result.Stderr)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("Expected exit code 0, got %d", result.ExitCode)
+	}
+}
+
+func TestExecuteWithoutArgs(t *testing.T) {
+	result := Execute(testTree())
+	if result.Err != nil {
+		t.Fatalf("Unexpected error: %v", result.Err)
+	}
+	if result.Command.Name() != "root" {
+		t.Errorf("Expected the root command rather than the arguments of the test binary, got %q", result.Command.Name())
+	}
+}
+
+func TestExecuteExitCode(t *testing.T) {
+	result := Execute(testTree(), "fail")
+	if result.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", result.ExitCode)
+	}
+	if !errors.Is(result.Err, exitError{3}) {
+		t.Errorf("Expected the error of the command, got %v", result.Err)
+	}
+
+	result = Execute(testTree(), "unknown")
+	if result.ExitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", result.ExitCode)
+	}
+}
+
+func TestExecuteWithEnv(t *testing.T) {
+	os.Setenv("COBRATEST_VALUE", "outer")
+	defer os.Unsetenv("COBRATEST_VALUE")
+
+	result := ExecuteWith(testTree(), Options{Args: []string{"env"}, Env: map[string]string{"COBRATEST_VALUE": "inner"}})
+	if result.Stdout != "inner" {
+		t.Errorf("Expected %q, got %q", "inner", result.Stdout)
+	}
+	if value := os.Getenv("COBRATEST_VALUE"); value != "outer" {
+		t.Errorf("Expected the environment to be restored, got %q", value)
+	}
+}
+
+func TestExecuteWithClearedEnv(t *testing.T) {
+	for key, value := range map[string]string{"NO_COLOR": "1", "LC_ALL": "de_DE", "COBRA_PAGER": "less", "ROOT_ERROR_FORMAT": "json"} {
+		if old, set := os.LookupEnv(key); set {
+			defer os.Setenv(key, old)
+		} else {
+			defer os.Unsetenv(key)
+		}
+		os.Setenv(key, value)
+	}
+
+	rootCmd := testTree()
+	var seen []string
+	rootCmd.Run = func(cmd *cobra.Command, args []string) {
+		for _, key := range []string{"NO_COLOR", "LC_ALL", "COBRA_PAGER", "ROOT_ERROR_FORMAT", "LANG"} {
+			if value, set := os.LookupEnv(key); set {
+				seen = append(seen, key+"="+value)
+			}
+		}
+	}
+
+	ExecuteWith(rootCmd, Options{Env: map[string]string{"LANG": "fr"}})
+	if len(seen) != 1 || seen[0] != "LANG=fr" {
+		t.Errorf("Expected only the variables of the options, got %q", seen)
+	}
+	if value := os.Getenv("NO_COLOR"); value != "1" {
+		t.Errorf("Expected the environment to be restored, got NO_COLOR=%q", value)
+	}
+}
+
+func TestExecuteWithDirAndStdin(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := ExecuteWith(testTree(), Options{Args: []string{"pwd"}, Dir: "testdata"})
+	if result.Stdout != "testdata" {
+		t.Errorf("Expected to run in testdata, got %q", result.Stdout)
+	}
+	if after, _ := os.Getwd(); after != wd {
+		t.Errorf("Expected the working directory to be restored, got %q", after)
+	}
+
+	result = ExecuteWith(testTree(), Options{Args: []string{"cat"}, Stdin: "input"})
+	if result.Stdout != "input" {
+		t.Errorf("Expected %q, got %q", "input", result.Stdout)
+	}
+}
+
+func TestExecuteWithTerminal(t *testing.T) {
+	rootCmd := testTree()
+	var interactive bool
+	rootCmd.Run = func(cmd *cobra.Command, args []string) {
+		interactive = cmd.Streams().Interactive()
+	}
+
+	ExecuteWith(rootCmd, Options{Terminal: &cobra.TerminalInfo{In: true}})
+	if !interactive {
+		t.Errorf("Expected the input to be a terminal")
+	}
+	Execute(rootCmd)
+	if interactive {
+		t.Errorf("Expected the input not to be a terminal")
+	}
+}
+
+func TestExecuteWithRestoresStreams(t *testing.T) {
+	rootCmd := testTree()
+	ExecuteWith(rootCmd, Options{Args: []string{"echo", "a"}, Terminal: &cobra.TerminalInfo{In: true}})
+
+	streams := rootCmd.Streams()
+	if streams.In != os.Stdin || streams.Out != os.Stdout || streams.Err != os.Stderr {
+		t.Errorf("Expected the standard streams to be restored, got %+v", streams)
+	}
+	if streams.Terminal != nil {
+		t.Errorf("Expected the detection of the terminals to be restored, got %+v", streams.Terminal)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobratest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Completion is the answer of a command tree to a completion request.
+type Completion struct {
+	// Values are the completions, each possibly followed by a tab and its description.
+	Values []string
+	// Directive is the shell completion directive returned with the completions.
+	Directive cobra.ShellCompDirective
+	// Result is the result of executing the completion request.
+	Result *Result
+}
+
+// Complete requests the completions of root for args, as a shell does when the user
+// presses tab. The last argument is the word being completed; pass "" to complete
+// a new word.
+func Complete(root *cobra.Command, args ...string) (*Completion, error) {
+	return CompleteWith(root, Options{Args: args})
+}
+
+// CompleteWith requests the completions of root for opts.Args in the environment
+// described by opts, see Complete.
+func CompleteWith(root *cobra.Command, opts Options) (*Completion, error) {
+	opts.Args = append([]string{cobra.ShellCompRequestCmd}, opts.Args...)
+	result := ExecuteWith(root, opts)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	values, directive, err := ParseCompletion(result.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &Completion{Values: values, Directive: directive, Result: result}, nil
+}
+
+// ParseCompletion parses the output of a completion request into the completions
+// and the directive given by its ":<directive>" trailer.
+func ParseCompletion(output string) ([]string, cobra.ShellCompDirective, error) {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	trailer := lines[len(lines)-1]
+	if !strings.HasPrefix(trailer, ":") {
+		return nil, 0, fmt.Errorf("missing completion directive in %q", output)
+	}
+	directive, err := strconv.Atoi(trailer[1:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid completion directive %q", trailer)
+	}
+	values := []string{}
+	for _, line := range lines[:len(lines)-1] {
+		if line != "" {
+			values = append(values, line)
+		}
+	}
+	return values, cobra.ShellCompDirective(directive), nil
+}
+
+// Names returns the completions without their descriptions.
+func (c *Completion) Names() []string {
+	names := make([]string, 0, len(c.Values))
+	for _, value := range c.Values {
+		names = append(names, strings.SplitN(value, "\t", 2)[0])
+	}
+	return names
+}
@@ -0,0 +1,64 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobratest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestComplete(t *testing.T) {
+	completion, err := Complete(testTree(), "e")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"echo\tEcho the arguments", "env"}; !reflect.DeepEqual(completion.Values, expected) {
+		t.Errorf("Expected %q, got %q", expected, completion.Values)
+	}
+	if completion.Directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected the NoFileComp directive, got %d", completion.Directive)
+	}
+
+	completion, err = Complete(testTree(), "echo", "--format", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"text", "json"}; !reflect.DeepEqual(completion.Names(), expected) {
+		t.Errorf("Expected %q, got %q", expected, completion.Names())
+	}
+}
+
+func TestParseCompletion(t *testing.T) {
+	values, directive, err := ParseCompletion("a\nb\tdesc\n:4\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(values, []string{"a", "b\tdesc"}) || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Unexpected completion %q with directive %d", values, directive)
+	}
+
+	values, directive, err = ParseCompletion(":0\n")
+	if err != nil || len(values) != 0 || directive != cobra.ShellCompDirectiveDefault {
+		t.Errorf("Unexpected completion %q with directive %d: %v", values, directive, err)
+	}
+
+	for _, output := range []string{"", "a\nb\n", "a\n:x\n"} {
+		if _, _, err := ParseCompletion(output); err == nil {
+			t.Errorf("Expected an error for %q", output)
+		}
+	}
+}
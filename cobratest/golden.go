@@ -0,0 +1,72 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobratest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Update makes AssertGolden write the golden files instead of comparing them.
+// cobratest registers no flag by itself; see RegisterUpdateFlag.
+var Update bool
+
+// RegisterUpdateFlag registers the -update flag setting Update in fs, such as
+// flag.CommandLine from the init function of a test file, so that
+// "go test -update" rewrites the golden files.
+func RegisterUpdateFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&Update, "update", false, "update the golden files")
+}
+
+// GoldenPath returns the path of the golden file name, in the testdata directory.
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// AssertGolden fails t if got differs from the golden file name in the testdata
+// directory. When Update is set, the golden file is written instead.
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := GoldenPath(name)
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create the golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Unable to update the golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read the golden file, set cobratest.Update to create it: %v", err)
+	}
+	if got != string(expected) {
+		t.Errorf("Output differs from %s, set cobratest.Update to update it.\nExpected:\n%s\nGot:\n%s", path, expected, got)
+	}
+}
+
+// AssertGolden fails t if the output of the command differs from the golden
+// files name.stdout and name.stderr, see AssertGolden.
+func (r *Result) AssertGolden(t testing.TB, name string) {
+	t.Helper()
+	AssertGolden(t, name+".stdout", r.Stdout)
+	AssertGolden(t, name+".stderr", r.Stderr)
+}
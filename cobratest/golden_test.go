@@ -0,0 +1,70 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobratest
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+)
+
+func init() {
+	RegisterUpdateFlag(flag.CommandLine)
+}
+
+// recordingTB records the failures of assertions instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func TestAssertGolden(t *testing.T) {
+	result := Execute(testTree(), "echo", "--help")
+	result.AssertGolden(t, "echo_help")
+}
+
+func TestAssertGoldenMismatch(t *testing.T) {
+	if Update {
+		t.Skip("golden files are being updated")
+	}
+
+	tb := &recordingTB{TB: t}
+	AssertGolden(tb, "echo_help.stdout", "something else")
+	if len(tb.failures) != 1 {
+		t.Errorf("Expected a mismatch, got %q", tb.failures)
+	}
+
+	tb = &recordingTB{TB: t}
+	AssertGolden(tb, "missing", "")
+	if len(tb.failures) != 1 {
+		t.Errorf("Expected a missing golden file to fail, got %q", tb.failures)
+	}
+}
+
+func TestGoldenPath(t *testing.T) {
+	if path := GoldenPath("help"); path != "testdata/help.golden" && path != "testdata\\help.golden" {
+		t.Errorf("Unexpected golden path %q", path)
+	}
+}
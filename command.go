@@ -133,6 +133,9 @@ type Command struct {
 	Run func(cmd *Command, args []string)
 	// RunE: Run but returns an error.
 	RunE func(cmd *Command, args []string) error
+	// RunOutput: Run but returns data, which is rendered in the format chosen with the
+	// '--output' flag, as configured by Output.
+	RunOutput func(cmd *Command, args []string) (interface{}, error)
 	// PostRun: run after the Run command.
 	PostRun func(cmd *Command, args []string)
 	// PostRunE: PostRun but returns an error.
@@ -191,12 +194,21 @@ type Command struct {
 	// errPrefix is the error message prefix defined by user.
 	errPrefix string
 
+	// helpWidth is the column width help and usage output is wrapped to, defined by user.
+	helpWidth int
+	// theme is the styling of help, usage and error output defined by user.
+	theme *Theme
+	// renderOut is the output help is destined for while UsageString renders it into a buffer.
+	renderOut io.Writer
+
 	// inReader is a reader defined by the user that replaces stdin
 	inReader io.Reader
 	// outWriter is a writer defined by the user that replaces stdout
 	outWriter io.Writer
 	// errWriter is a writer defined by the user that replaces stderr
 	errWriter io.Writer
+	// terminal describes the terminals of the streams instead of detecting them
+	terminal *TerminalInfo
 
 	// FParseErrWhitelist flag parse errors to be ignored
 	FParseErrWhitelist FParseErrWhitelist
@@ -204,6 +216,24 @@ type Command struct {
 	// CompletionOptions is a set of options to control the handling of shell completion
 	CompletionOptions CompletionOptions
 
+	// PagerOptions is a set of options to control paging of help output
+	PagerOptions PagerOptions
+
+	// Confirm is a set of options to ask for a confirmation before running the command
+	Confirm ConfirmOptions
+
+	// Output is a set of options to control the rendering of the data returned by RunOutput
+	Output OutputOptions
+
+	// ErrorOptions is a set of options to control the reporting of errors
+	ErrorOptions ErrorOptions
+
+	// Logging is a set of options to control the logging of the program
+	Logging LoggingOptions
+
+	// bindings are the struct fields bound to the flags and arguments by Bind.
+	bindings []*fieldBinding
+
 	// commandsAreSorted defines, if command slice are sorted or not.
 	commandsAreSorted bool
 	// commandCalledAs is the name or alias value used to call this command.
@@ -254,6 +284,11 @@ type Command struct {
 	// SuggestionsMinimumDistance defines minimum levenshtein distance to display suggestions.
 	// Must be > 0.
 	SuggestionsMinimumDistance int
+
+	// PromptMissing prompts for the missing required flags and positional arguments
+	// instead of failing, when the input of the command is a terminal.
+	// It is inherited by subcommands.
+	PromptMissing bool
 }
 
 // Context returns underlying command context. If command was executed
@@ -361,6 +396,12 @@ func (c *Command) SetErrPrefix(s string) {
 	c.errPrefix = s
 }
 
+// SetHelpWidth sets the column width help and usage output is wrapped to,
+// regardless of EnableHelpWrapping. A width of 0 restores terminal detection.
+func (c *Command) SetHelpWidth(width int) {
+	c.helpWidth = width
+}
+
 // SetGlobalNormalizationFunc sets a normalization function to all flag sets and also to child commands.
 // The user should not have a cyclic dependency on commands.
 func (c *Command) SetGlobalNormalizationFunc(n func(f *flag.FlagSet, name string) flag.NormalizedName) {
@@ -472,8 +513,10 @@ func (c *Command) HelpFunc() func(*Command, []string) {
 // Help puts out the help for the command.
 // Used when a user calls help [command].
 // Can be defined by user by overriding HelpFunc.
+// If paging is enabled through PagerOptions, help that does not fit in the
+// terminal is piped into a pager.
 func (c *Command) Help() error {
-	c.HelpFunc()(c, []string{})
+	c.pageHelp(func() { c.HelpFunc()(c, []string{}) })
 	return nil
 }
 
@@ -483,6 +526,10 @@ func (c *Command) UsageString() string {
 	tmpOutput := c.outWriter
 	tmpErr := c.errWriter
 
+	// The buffer is not a terminal, keep detecting the terminal on the real output
+	tmpRender := c.renderOut
+	c.renderOut = c.terminalOut(c.OutOrStdout())
+
 	bb := new(bytes.Buffer)
 	c.outWriter = bb
 	c.errWriter = bb
@@ -492,6 +539,28 @@ func (c *Command) UsageString() string {
 	// Setting things back to normal
 	c.outWriter = tmpOutput
 	c.errWriter = tmpErr
+	c.renderOut = tmpRender
+
+	return bb.String()
+}
+
+// HelpString returns the help of the command as it is written to a file or a
+// pipe, that is without styling or paging.
+func (c *Command) HelpString() string {
+	tmpOutput := c.outWriter
+	tmpErr := c.errWriter
+	tmpRender := c.renderOut
+
+	bb := new(bytes.Buffer)
+	c.outWriter = bb
+	c.errWriter = bb
+	c.renderOut = bb
+
+	c.HelpFunc()(c, []string{})
+
+	c.outWriter = tmpOutput
+	c.errWriter = tmpErr
+	c.renderOut = tmpRender
 
 	return bb.String()
 }
@@ -542,6 +611,29 @@ func (c *Command) NamePadding() int {
 	return c.parent.commandsMaxNameLen
 }
 
+// HelpWidth returns the column width help and usage output is wrapped to,
+// or 0 if it should not be wrapped.
+func (c *Command) HelpWidth() int {
+	for p := c; p != nil; p = p.Parent() {
+		if p.helpWidth > 0 {
+			return p.helpWidth
+		}
+	}
+	if !EnableHelpWrapping {
+		return 0
+	}
+	return c.Streams().Width()
+}
+
+// terminalOut returns the writer whose terminal determines how output written
+// to w is rendered. It is w unless the output is being rendered into a buffer.
+func (c *Command) terminalOut(w io.Writer) io.Writer {
+	if c.renderOut != nil {
+		return c.renderOut
+	}
+	return w
+}
+
 // UsageTemplate returns usage template for the command.
 func (c *Command) UsageTemplate() string {
 	if c.usageTemplate != "" {
@@ -551,35 +643,35 @@ func (c *Command) UsageTemplate() string {
 	if c.HasParent() {
 		return c.parent.UsageTemplate()
 	}
-	return `Usage:{{if .Runnable}}
+	return `{{styleHeading (translate "Usage:")}}{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
   {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
 
-Aliases:
+{{styleHeading (translate "Aliases:")}}
   {{.NameAndAliases}}{{end}}{{if .HasExample}}
 
-Examples:
-{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+{{styleHeading (translate "Examples:")}}
+{{.Example | translate}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
 
-Available Commands:{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+{{styleHeading (translate "Available Commands:")}}{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+{{wrapHanging (printf "  %s " (styleCommand (rpad .Name .NamePadding))) $.HelpWidth (translate .Short)}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
 
-{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+{{styleHeading (translate .Title)}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+{{wrapHanging (printf "  %s " (styleCommand (rpad .Name .NamePadding))) $.HelpWidth (translate .Short)}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
 
-Additional Commands:{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+{{styleHeading (translate "Additional Commands:")}}{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+{{wrapHanging (printf "  %s " (styleCommand (rpad .Name .NamePadding))) $.HelpWidth (translate .Short)}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
 
-Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+{{styleHeading (translate "Flags:")}}
+{{flagUsages .LocalFlags .HelpWidth | trimTrailingWhitespaces | styleFlagUsages}}{{end}}{{if .HasAvailableInheritedFlags}}
 
-Global Flags:
-{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+{{styleHeading (translate "Global Flags:")}}
+{{flagUsages .InheritedFlags .HelpWidth | trimTrailingWhitespaces | styleFlagUsages}}{{end}}{{if .HasHelpSubCommands}}
 
-Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
-  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+{{styleHeading (translate "Additional help topics:")}}{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+{{wrapHanging (printf "  %s " (styleCommand (rpad .CommandPath .CommandPathPadding))) $.HelpWidth (translate .Short)}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
 
-Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+{{printf (translate "Use \"%s [command] --help\" for more information about a command.") .CommandPath}}{{end}}
 `
 }
 
@@ -592,7 +684,7 @@ func (c *Command) HelpTemplate() string {
 	if c.HasParent() {
 		return c.parent.HelpTemplate()
 	}
-	return `{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
+	return `{{with (or .Long .Short)}}{{. | translate | trimTrailingWhitespaces | wrap $.HelpWidth}}
 
 {{end}}{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}`
 }
@@ -606,7 +698,7 @@ func (c *Command) VersionTemplate() string {
 	if c.HasParent() {
 		return c.parent.VersionTemplate()
 	}
-	return `{{with .Name}}{{printf "%s " .}}{{end}}{{printf "version %s" .Version}}
+	return `{{with .Name}}{{printf "%s " .}}{{end}}{{printf (translate "version %s") .Version}}
 `
 }
 
@@ -619,11 +711,11 @@ func (c *Command) ErrPrefix() string {
 	if c.HasParent() {
 		return c.parent.ErrPrefix()
 	}
-	return "Error:"
+	return Translate("Error:")
 }
 
 func hasNoOptDefVal(name string, fs *flag.FlagSet) bool {
-	flag := fs.Lookup(name)
+	flag, _ := lookupFlag(fs, name)
 	if flag == nil {
 		return false
 	}
@@ -750,20 +842,18 @@ func (c *Command) Find(args []string) (*Command, []string, error) {
 }
 
 func (c *Command) findSuggestions(arg string) string {
+	return suggestionsText(c.commandSuggestions(arg))
+}
+
+// commandSuggestions returns the commands suggested for arg, unless suggestions are disabled.
+func (c *Command) commandSuggestions(arg string) []string {
 	if c.DisableSuggestions {
-		return ""
+		return nil
 	}
 	if c.SuggestionsMinimumDistance <= 0 {
 		c.SuggestionsMinimumDistance = 2
 	}
-	var sb strings.Builder
-	if suggestions := c.SuggestionsFor(arg); len(suggestions) > 0 {
-		sb.WriteString("\n\nDid you mean this?\n")
-		for _, s := range suggestions {
-			_, _ = fmt.Fprintf(&sb, "\t%v\n", s)
-		}
-	}
-	return sb.String()
+	return c.PathSuggestionsFor(arg)
 }
 
 func (c *Command) findNext(next string) *Command {
@@ -879,17 +969,17 @@ func (c *Command) execute(a []string) (err error) {
 	}
 
 	if len(c.Deprecated) > 0 {
-		c.Printf("Command %q is deprecated, %s\n", c.Name(), c.Deprecated)
+		c.Printf(Translate("Command %q is deprecated, %s\n"), c.Name(), c.Deprecated)
 	}
 
-	// initialize help and version flag at the last point possible to allow for user
-	// overriding
-	c.InitDefaultHelpFlag()
-	c.InitDefaultVersionFlag()
+	// initialize help, version and the other default flags at the last point possible
+	// to allow for user overriding
+	c.initCommandDefaults()
+	c.resetBindings()
 
 	err = c.ParseFlags(a)
 	if err != nil {
-		return c.FlagErrorFunc()(c, err)
+		return c.FlagErrorFunc()(c, c.flagError(err))
 	}
 
 	// If help is called, regardless of other flags, return we want help.
@@ -935,9 +1025,29 @@ func (c *Command) execute(a []string) (err error) {
 		argWoFlags = a
 	}
 
+	prompter := c.newPrompter()
+	if prompter != nil {
+		argWoFlags = prompter.promptMissingArgs(argWoFlags)
+	}
+
+	c.Log().Debugf("resolved command %q, called as %q, with arguments %q", c.CommandPath(), c.CalledAs(), argWoFlags)
 	if err := c.ValidateArgs(argWoFlags); err != nil {
 		return err
 	}
+	if err := c.applyBindings(argWoFlags); err != nil {
+		return err
+	}
+	if prompter != nil {
+		prompter.promptMissingFlags()
+	}
+	if err := c.validateOutputFormat(); err != nil {
+		return err
+	}
+	// The confirmation is asked before the hooks run, so that nothing
+	// happens unless the user agrees.
+	if err := c.confirm(prompter, argWoFlags); err != nil {
+		return err
+	}
 
 	parents := make([]*Command, 0, 5)
 	for p := c; p != nil; p = p.Parent() {
@@ -952,6 +1062,9 @@ func (c *Command) execute(a []string) (err error) {
 		}
 	}
 	for _, p := range parents {
+		if p.PersistentPreRunE != nil || p.PersistentPreRun != nil {
+			c.Log().Debugf("running the persistent pre-run hook of %q", p.CommandPath())
+		}
 		if p.PersistentPreRunE != nil {
 			if err := p.PersistentPreRunE(c, argWoFlags); err != nil {
 				return err
@@ -985,6 +1098,14 @@ func (c *Command) execute(a []string) (err error) {
 		if err := c.RunE(c, argWoFlags); err != nil {
 			return err
 		}
+	} else if c.RunOutput != nil {
+		data, err := c.RunOutput(c, argWoFlags)
+		if err != nil {
+			return err
+		}
+		if err := c.PrintOutput(data); err != nil {
+			return err
+		}
 	} else {
 		c.Run(c, argWoFlags)
 	}
@@ -1066,10 +1187,8 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		preExecHookFn(c)
 	}
 
-	// initialize help at the last point to allow for user overriding
-	c.InitDefaultHelpCmd()
-	// initialize completion at the last point to allow for user overriding
-	c.InitDefaultCompletionCmd()
+	// initialize the default commands and flags at the last point to allow for user overriding
+	c.initRootDefaults()
 
 	// Now that all commands have been created, let's make sure all groups
 	// are properly created also
@@ -1096,9 +1215,9 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		if cmd != nil {
 			c = cmd
 		}
-		if !c.SilenceErrors {
-			c.PrintErrln(c.ErrPrefix(), err.Error())
-			c.PrintErrf("Run '%v --help' for usage.\n", c.CommandPath())
+		if !c.SilenceErrors && !c.printJSONError(err, args) {
+			c.PrintErrln(c.styledErrPrefix(c.ErrOrStderr()), err.Error())
+			c.PrintErrf(Translate("Run '%v --help' for usage.\n"), c.CommandPath())
 		}
 		return c, err
 	}
@@ -1119,19 +1238,21 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		// Always show help if requested, even if SilenceErrors is in
 		// effect
 		if errors.Is(err, flag.ErrHelp) {
-			cmd.HelpFunc()(cmd, args)
+			cmd.pageHelp(func() { cmd.HelpFunc()(cmd, args) })
 			return cmd, nil
 		}
 
 		// If root command has SilenceErrors flagged,
 		// all subcommands should respect it
-		if !cmd.SilenceErrors && !c.SilenceErrors {
-			c.PrintErrln(cmd.ErrPrefix(), err.Error())
+		if !cmd.SilenceErrors && !c.SilenceErrors && !cmd.printJSONError(err, args) {
+			c.PrintErrln(cmd.styledErrPrefix(c.ErrOrStderr()), err.Error())
 		}
 
 		// If root command has SilenceUsage flagged,
-		// all subcommands should respect it
-		if !cmd.SilenceUsage && !c.SilenceUsage {
+		// all subcommands should respect it.
+		// A declined confirmation is not a usage error,
+		// and errors reported as JSON are not followed by the usage.
+		if !cmd.SilenceUsage && !c.SilenceUsage && !errors.Is(err, ErrNotConfirmed) && c.errorFormat(args) != errorFormatJSON {
 			c.Println(cmd.UsageString())
 		}
 	}
@@ -1164,7 +1285,11 @@ func (c *Command) ValidateRequiredFlags() error {
 	})
 
 	if len(missingFlagNames) > 0 {
-		return fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missingFlagNames, `", "`))
+		return &CommandError{
+			Kind:  ErrorKindRequiredFlag,
+			Flags: missingFlagNames,
+			Err:   fmt.Errorf(Translate(`required flag(s) "%s" not set`), strings.Join(missingFlagNames, `", "`)),
+		}
 	}
 	return nil
 }
@@ -1188,12 +1313,9 @@ func (c *Command) checkCommandGroups() {
 func (c *Command) InitDefaultHelpFlag() {
 	c.mergePersistentFlags()
 	if c.Flags().Lookup("help") == nil {
-		usage := "help for "
-		name := c.displayName()
-		if name == "" {
-			usage += "this command"
-		} else {
-			usage += name
+		usage := Translate("help for this command")
+		if name := c.displayName(); name != "" {
+			usage = fmt.Sprintf(Translate("help for %s"), name)
 		}
 		c.Flags().BoolP("help", "h", false, usage)
 		_ = c.Flags().SetAnnotation("help", FlagSetByCobraAnnotation, []string{"true"})
@@ -1211,11 +1333,9 @@ func (c *Command) InitDefaultVersionFlag() {
 
 	c.mergePersistentFlags()
 	if c.Flags().Lookup("version") == nil {
-		usage := "version for "
-		if c.Name() == "" {
-			usage += "this command"
-		} else {
-			usage += c.Name()
+		usage := Translate("version for this command")
+		if c.Name() != "" {
+			usage = fmt.Sprintf(Translate("version for %s"), c.Name())
 		}
 		if c.Flags().ShorthandLookup("v") == nil {
 			c.Flags().BoolP("version", "v", false, usage)
@@ -1226,6 +1346,35 @@ func (c *Command) InitDefaultVersionFlag() {
 	}
 }
 
+// InitDefaults adds the default commands and flags Execute adds before running c:
+// the help and completion commands and the persistent flags of the root command,
+// and the help, version, confirmation and output flags of c. Once they are added,
+// the help of c is the one printed by --help. Commands and flags already defined
+// are not replaced.
+func (c *Command) InitDefaults() {
+	c.Root().initRootDefaults()
+	c.initCommandDefaults()
+}
+
+// initRootDefaults adds the default commands and persistent flags of the root
+// command c. ExecuteC adds them before the command to run is found.
+func (c *Command) initRootDefaults() {
+	c.InitDefaultHelpCmd()
+	c.InitDefaultCompletionCmd()
+	c.initNoPagerFlag()
+	c.initErrorFormatFlag()
+	c.initLoggingFlags()
+}
+
+// initCommandDefaults adds the default flags of c. They are added once c is found
+// to be the command to run, so that they do not change how it is found.
+func (c *Command) initCommandDefaults() {
+	c.InitDefaultHelpFlag()
+	c.InitDefaultVersionFlag()
+	c.initConfirmFlag()
+	c.initOutputFlag()
+}
+
 // InitDefaultHelpCmd adds default help command to c.
 // It is called automatically by executing the c or by calling help and usage.
 // If c already has help command or c has no subcommands, it will do nothing.
@@ -1237,9 +1386,9 @@ func (c *Command) InitDefaultHelpCmd() {
 	if c.helpCommand == nil {
 		c.helpCommand = &Command{
 			Use:   "help [command]",
-			Short: "Help about any command",
-			Long: `Help provides help for any command in the application.
-Simply type ` + c.displayName() + ` help [path to command] for full details.`,
+			Short: Translate("Help about any command"),
+			Long: fmt.Sprintf(Translate(`Help provides help for any command in the application.
+Simply type %s help [path to command] for full details.`), c.displayName()),
 			ValidArgsFunction: func(c *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
 				var completions []string
 				cmd, _, e := c.Root().Find(args)
@@ -1259,19 +1408,31 @@ Simply type ` + c.displayName() + ` help [path to command] for full details.`,
 				}
 				return completions, ShellCompDirectiveNoFileComp
 			},
-			Run: func(c *Command, args []string) {
+			RunE: func(c *Command, args []string) error {
+				if term, _ := c.Flags().GetString(helpSearchFlagName); term != "" {
+					c.Root().printHelpSearch(c, strings.Join(append([]string{term}, args...), " "))
+					return nil
+				}
+				format, _ := c.Flags().GetString(helpFormatFlagName)
+				if !stringInSlice(format, helpFormats) {
+					return fmt.Errorf(Translate("unsupported help format %q, must be one of: %s"), format, strings.Join(helpFormats, ", "))
+				}
 				cmd, _, e := c.Root().Find(args)
 				if cmd == nil || e != nil {
-					c.Printf("Unknown help topic %#q\n", args)
-					CheckErr(c.Root().Usage())
-				} else {
-					cmd.InitDefaultHelpFlag()    // make possible 'help' flag to be shown
-					cmd.InitDefaultVersionFlag() // make possible 'version' flag to be shown
-					CheckErr(cmd.Help())
+					c.Printf(Translate("Unknown help topic %#q\n"), args)
+					return c.Root().Usage()
+				} else if format == helpFormatJSON {
+					return cmd.writeDescriptionJSON(c.OutOrStdout())
 				}
+				cmd.InitDefaultHelpFlag()    // make possible 'help' flag to be shown
+				cmd.InitDefaultVersionFlag() // make possible 'version' flag to be shown
+				return cmd.Help()
 			},
 			GroupID: c.helpCommandGroupID,
 		}
+		c.helpCommand.Flags().String(helpSearchFlagName, "", Translate(helpSearchFlagUsage))
+		c.helpCommand.Flags().String(helpFormatFlagName, helpFormatText, Translate(helpFormatFlagUsage))
+		_ = c.helpCommand.RegisterFlagCompletionFunc(helpFormatFlagName, FixedCompletions(helpFormats, ShellCompDirectiveNoFileComp))
 	}
 	c.RemoveCommand(c.helpCommand)
 	c.AddCommand(c.helpCommand)
@@ -1556,7 +1717,7 @@ func (c *Command) HasExample() bool {
 
 // Runnable determines if the command is itself runnable.
 func (c *Command) Runnable() bool {
-	return c.Run != nil || c.RunE != nil
+	return c.Run != nil || c.RunE != nil || c.RunOutput != nil
 }
 
 // HasSubCommands determines if the command has children commands.
@@ -1841,7 +2002,17 @@ func (c *Command) ParseFlags(args []string) error {
 	// do it here after merging all flags and just before parse
 	c.Flags().ParseErrorsWhitelist = flag.ParseErrorsWhitelist(c.FParseErrWhitelist)
 
-	err := c.Flags().Parse(args)
+	var err error
+	if args, err = expandNegatedFlags(args, c.Flags()); err != nil {
+		return err
+	}
+	if EnableFlagPrefixMatching {
+		if args, err = expandFlagPrefixes(args, c.Flags()); err != nil {
+			return err
+		}
+	}
+
+	err = c.Flags().Parse(args)
 	// Print warnings if they occurred (e.g. deprecated flag messages).
 	if c.flagErrorBuf.Len()-beforeErrorBufLen > 0 && err == nil {
 		c.Print(c.flagErrorBuf.String())
@@ -0,0 +1,119 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"sort"
+	"strings"
+)
+
+// commandSuggestion is a command suggested for a mistyped command name.
+type commandSuggestion struct {
+	// path is the path of the command relative to the command the name was given to.
+	path     string
+	distance int
+	depth    int
+}
+
+// PathSuggestionsFor returns the paths, relative to c, of the commands in the
+// tree under c which are similar to typedName. Besides the names of the direct
+// children of c, as compared by SuggestionsFor, typedName is compared with the
+// names and SuggestFor entries of the commands at any depth, and with their
+// relative paths spelled as a single word, so that "delete-user" or "userdelete"
+// suggest "user delete". The suggestions are ranked from the closest match.
+func (c *Command) PathSuggestionsFor(typedName string) []string {
+	maxDistance := c.SuggestionsMinimumDistance
+	if maxDistance <= 0 {
+		maxDistance = 2
+	}
+	typedWords := splitCommandWords(typedName)
+
+	var found []commandSuggestion
+	var visit func(cmd *Command, words []string)
+	visit = func(cmd *Command, words []string) {
+		for _, child := range cmd.commands {
+			if !child.IsAvailableCommand() {
+				continue
+			}
+			path := append(append([]string{}, words...), child.Name())
+			if distance, ok := commandPathDistance(typedName, typedWords, child, path, maxDistance); ok {
+				found = append(found, commandSuggestion{path: strings.Join(path, " "), distance: distance, depth: len(path)})
+			}
+			visit(child, path)
+		}
+	}
+	visit(c, nil)
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+		return found[i].depth < found[j].depth
+	})
+	suggestions := []string{}
+	for _, s := range found {
+		if !stringInSlice(s.path, suggestions) {
+			suggestions = append(suggestions, s.path)
+		}
+	}
+	return suggestions
+}
+
+// commandPathDistance returns the distance between the name typed by the user
+// and the command cmd found at path, and whether cmd should be suggested.
+func commandPathDistance(typedName string, typedWords []string, cmd *Command, path []string, maxDistance int) (int, bool) {
+	for _, explicitSuggestion := range cmd.SuggestFor {
+		if strings.EqualFold(typedName, explicitSuggestion) {
+			return 0, true
+		}
+	}
+
+	distance := ld(typedName, cmd.Name(), true)
+	if len(path) == 1 {
+		// Direct children are also suggested for a prefix of their name, as
+		// SuggestionsFor does.
+		prefix := strings.HasPrefix(strings.ToLower(cmd.Name()), strings.ToLower(typedName))
+		return distance, distance <= maxDistance || prefix
+	}
+
+	// The words of the path, typed as a single word in order or transposed.
+	if d := ld(strings.Join(typedWords, ""), strings.Join(path, ""), true); d < distance {
+		distance = d
+	}
+	if len(typedWords) == len(path) {
+		if d := ld(strings.Join(sortedWords(typedWords), ""), strings.Join(sortedWords(path), ""), true); d < distance {
+			distance = d
+		}
+	}
+	return distance, distance <= maxDistance
+}
+
+// splitCommandWords splits a name typed as a single word on the separators
+// commonly used to join words.
+func splitCommandWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == '-' || r == '_' || r == ':' || r == '.'
+	})
+}
+
+// sortedWords returns a sorted, lower-cased copy of words.
+func sortedWords(words []string) []string {
+	sorted := make([]string, len(words))
+	for i, w := range words {
+		sorted[i] = strings.ToLower(w)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,99 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathSuggestionsFor(t *testing.T) {
+	rootCmd := &Command{Use: "tool", Run: emptyRun}
+	userCmd := &Command{Use: "user", Run: emptyRun}
+	userDeleteCmd := &Command{Use: "delete", Run: emptyRun, SuggestFor: []string{"purge"}}
+	userListCmd := &Command{Use: "list", Run: emptyRun}
+	groupCmd := &Command{Use: "group", Run: emptyRun}
+	groupListCmd := &Command{Use: "list", Run: emptyRun}
+	hiddenCmd := &Command{Use: "secret", Run: emptyRun, Hidden: true}
+	hiddenChildCmd := &Command{Use: "delete", Run: emptyRun}
+	userCmd.AddCommand(userDeleteCmd, userListCmd)
+	groupCmd.AddCommand(groupListCmd)
+	hiddenCmd.AddCommand(hiddenChildCmd)
+	rootCmd.AddCommand(userCmd, groupCmd, hiddenCmd)
+
+	tests := []struct {
+		name     string
+		typed    string
+		expected []string
+	}{
+		{
+			name:     "Direct child",
+			typed:    "usr",
+			expected: []string{"user"},
+		},
+		{
+			name:     "Transposed path",
+			typed:    "delete-user",
+			expected: []string{"user delete"},
+		},
+		{
+			name:     "Path as a single word",
+			typed:    "userdelete",
+			expected: []string{"user delete"},
+		},
+		{
+			name:     "Name of a nested command",
+			typed:    "delte",
+			expected: []string{"user delete"},
+		},
+		{
+			name:     "Equally close commands in tree order",
+			typed:    "lst",
+			expected: []string{"user list", "group list"},
+		},
+		{
+			name:     "SuggestFor of a nested command",
+			typed:    "purge",
+			expected: []string{"user delete"},
+		},
+		{
+			name:     "Nothing similar",
+			typed:    "xyzzy",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rootCmd.PathSuggestionsFor(tt.typed)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected suggestions: %q\nGot: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnknownCommandPathSuggestions(t *testing.T) {
+	rootCmd := &Command{Use: "tool", Run: emptyRun}
+	userCmd := &Command{Use: "user", Run: emptyRun}
+	userCmd.AddCommand(&Command{Use: "delete", Run: emptyRun})
+	rootCmd.AddCommand(userCmd)
+
+	output, _ := executeCommand(rootCmd, "delete-user")
+	expected := "Error: unknown command \"delete-user\" for \"tool\"\n\nDid you mean this?\n\tuser delete\n\nRun 'tool --help' for usage.\n"
+	if output != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, output)
+	}
+}
@@ -1079,6 +1079,72 @@ func TestHelpExecutedOnNonRunnableChild(t *testing.T) {
 	checkStringContains(t, output, childCmd.Long)
 }
 
+func TestHelpNotWrappedByDefault(t *testing.T) {
+	rootCmd := &Command{Use: "root", Long: strings.Repeat("word ", 40), Run: emptyRun}
+	rootCmd.Flags().String("name", "", strings.Repeat("usage ", 30))
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringContains(t, output, strings.TrimSpace(strings.Repeat("word ", 40)))
+	checkStringContains(t, output, strings.TrimSpace(strings.Repeat("usage ", 30)))
+}
+
+func TestHelpWrappedToHelpWidth(t *testing.T) {
+	rootCmd := &Command{Use: "root", Long: "a long description of the root command that really needs wrapping", Run: emptyRun}
+	childCmd := &Command{Use: "child", Short: "a short description of the child command that needs wrapping", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+	rootCmd.Flags().String("name", "", "the name of the thing to operate on, which is rather long")
+	rootCmd.SetHelpWidth(60)
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringContains(t, output, "a long description of the root command that really needs\nwrapping")
+	checkStringContains(t, output, "  child       a short description of the child command that\n              needs wrapping")
+	checkStringContains(t, output, "      --name string   the name of the thing to operate\n                      on, which is rather long")
+}
+
+func TestHelpExampleNotWrapped(t *testing.T) {
+	example := "  root --name a-rather-long-value --other another-long-value --third yet-another-value\n  root    --name x"
+	rootCmd := &Command{Use: "root", Example: example, Run: emptyRun}
+	rootCmd.SetHelpWidth(40)
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringContains(t, output, example)
+}
+
+func TestHelpWidthFromColumns(t *testing.T) {
+	EnableHelpWrapping = true
+	defer func() { EnableHelpWrapping = defaultHelpWrapping }()
+	os.Setenv("COLUMNS", "50")
+	defer os.Unsetenv("COLUMNS")
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.SetOut(new(bytes.Buffer))
+	if got := rootCmd.HelpWidth(); got != 50 {
+		t.Errorf("Expected help width 50, got %d", got)
+	}
+
+	os.Unsetenv("COLUMNS")
+	if got := rootCmd.HelpWidth(); got != defaultTerminalWidth {
+		t.Errorf("Expected help width %d, got %d", defaultTerminalWidth, got)
+	}
+
+	EnableHelpWrapping = false
+	if got := rootCmd.HelpWidth(); got != 0 {
+		t.Errorf("Expected wrapping to be disabled, got width %d", got)
+	}
+}
+
 func TestVersionFlagExecuted(t *testing.T) {
 	rootCmd := &Command{Use: "root", Version: "1.0.0", Run: emptyRun}
 
@@ -2070,6 +2136,24 @@ func TestUsageStringRedirected(t *testing.T) {
 	}
 }
 
+func TestHelpStringRedirected(t *testing.T) {
+	out := new(bytes.Buffer)
+	c := &Command{Use: "c", Long: "c help"}
+	c.SetOut(out)
+	c.SetHelpFunc(func(cmd *Command, args []string) {
+		cmd.Print("[stdout1]")
+		cmd.PrintErr("[stderr2]")
+	})
+
+	expected := "[stdout1][stderr2]"
+	if got := c.HelpString(); got != expected {
+		t.Errorf("Expected help string %q, got %q", expected, got)
+	}
+	if out.Len() != 0 || c.OutOrStdout() != out {
+		t.Errorf("Expected the output to be restored")
+	}
+}
+
 func TestCommandPrintRedirection(t *testing.T) {
 	errBuff, outBuff := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
 	root := &Command{
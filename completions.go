@@ -201,13 +201,13 @@ func (c *Command) initCompleteCmd(args []string) {
 		Hidden:                true,
 		DisableFlagParsing:    true,
 		Args:                  MinimumNArgs(1),
-		Short:                 "Request shell completion choices for the specified command-line",
-		Long: fmt.Sprintf("%[2]s is a special command that is used by the shell completion logic\n%[1]s",
-			"to request completion choices for the specified command-line.", ShellCompRequestCmd),
+		Short:                 Translate("Request shell completion choices for the specified command-line"),
+		Long: fmt.Sprintf(Translate("%[2]s is a special command that is used by the shell completion logic\n%[1]s"),
+			Translate("to request completion choices for the specified command-line."), ShellCompRequestCmd),
 		Run: func(cmd *Command, args []string) {
 			finalCmd, completions, directive, err := cmd.getCompletions(args)
 			if err != nil {
-				CompErrorln(err.Error())
+				cmd.compErrorln(err.Error())
 				// Keep going for multiple reasons:
 				// 1- There could be some valid completions even though there was an error
 				// 2- Even without completions, we need to print the directive
@@ -304,12 +304,11 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 
 	// These flags are normally added when `execute()` is called on `finalCmd`,
 	// however, when doing completion, we don't call `finalCmd.execute()`.
-	// Let's add the --help and --version flag ourselves but only if the finalCmd
-	// has not disabled flag parsing; if flag parsing is disabled, it is up to the
-	// finalCmd itself to handle the completion of *all* flags.
+	// Let's add the --help, --version, --yes and --output flags ourselves but only if
+	// the finalCmd has not disabled flag parsing; if flag parsing is disabled, it is up
+	// to the finalCmd itself to handle the completion of *all* flags.
 	if !finalCmd.DisableFlagParsing {
-		finalCmd.InitDefaultHelpFlag()
-		finalCmd.InitDefaultVersionFlag()
+		finalCmd.initCommandDefaults()
 	}
 
 	// Check if we are doing flag value completion before parsing the flags.
@@ -563,6 +562,11 @@ func getFlagNameCompletions(flag *pflag.Flag, toComplete string) []string {
 		// }
 	}
 
+	flagName = "--" + negatedFlagPrefix + flag.Name
+	if IsFlagNegatable(flag) && strings.HasPrefix(flagName, toComplete) {
+		completions = append(completions, fmt.Sprintf("%s\t%s", flagName, flag.Usage))
+	}
+
 	flagName = "-" + flag.Shorthand
 	if len(flag.Shorthand) > 0 && strings.HasPrefix(flagName, toComplete) {
 		completions = append(completions, fmt.Sprintf("%s\t%s", flagName, flag.Usage))
@@ -703,10 +707,10 @@ func (c *Command) InitDefaultCompletionCmd() {
 
 	completionCmd := &Command{
 		Use:   compCmdName,
-		Short: "Generate the autocompletion script for the specified shell",
-		Long: fmt.Sprintf(`Generate the autocompletion script for %[1]s for the specified shell.
+		Short: Translate("Generate the autocompletion script for the specified shell"),
+		Long: fmt.Sprintf(Translate(`Generate the autocompletion script for %[1]s for the specified shell.
 See each sub-command's help for details on how to use the generated script.
-`, c.Root().Name()),
+`), c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		Hidden:            c.CompletionOptions.HiddenDefaultCmd,
@@ -716,11 +720,11 @@ See each sub-command's help for details on how to use the generated script.
 
 	out := c.OutOrStdout()
 	noDesc := c.CompletionOptions.DisableDescriptions
-	shortDesc := "Generate the autocompletion script for %s"
+	shortDesc := Translate("Generate the autocompletion script for %s")
 	bash := &Command{
 		Use:   "bash",
 		Short: fmt.Sprintf(shortDesc, "bash"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for the bash shell.
+		Long: fmt.Sprintf(Translate(`Generate the autocompletion script for the bash shell.
 
 This script depends on the 'bash-completion' package.
 If it is not installed already, you can install it via your OS's package manager.
@@ -740,7 +744,7 @@ To load completions for every new session, execute once:
 	%[1]s completion bash > $(brew --prefix)/etc/bash_completion.d/%[1]s
 
 You will need to start a new shell for this setup to take effect.
-`, c.Root().Name()),
+`), c.Root().Name()),
 		Args:                  NoArgs,
 		DisableFlagsInUseLine: true,
 		ValidArgsFunction:     NoFileCompletions,
@@ -749,13 +753,13 @@ You will need to start a new shell for this setup to take effect.
 		},
 	}
 	if haveNoDescFlag {
-		bash.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		bash.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, Translate(compCmdNoDescFlagDesc))
 	}
 
 	zsh := &Command{
 		Use:   "zsh",
 		Short: fmt.Sprintf(shortDesc, "zsh"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for the zsh shell.
+		Long: fmt.Sprintf(Translate(`Generate the autocompletion script for the zsh shell.
 
 If shell completion is not already enabled in your environment you will need
 to enable it.  You can execute the following once:
@@ -777,7 +781,7 @@ To load completions for every new session, execute once:
 	%[1]s completion zsh > $(brew --prefix)/share/zsh/site-functions/_%[1]s
 
 You will need to start a new shell for this setup to take effect.
-`, c.Root().Name()),
+`), c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		RunE: func(cmd *Command, args []string) error {
@@ -788,13 +792,13 @@ You will need to start a new shell for this setup to take effect.
 		},
 	}
 	if haveNoDescFlag {
-		zsh.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		zsh.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, Translate(compCmdNoDescFlagDesc))
 	}
 
 	fish := &Command{
 		Use:   "fish",
 		Short: fmt.Sprintf(shortDesc, "fish"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for the fish shell.
+		Long: fmt.Sprintf(Translate(`Generate the autocompletion script for the fish shell.
 
 To load completions in your current shell session:
 
@@ -805,7 +809,7 @@ To load completions for every new session, execute once:
 	%[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
 
 You will need to start a new shell for this setup to take effect.
-`, c.Root().Name()),
+`), c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		RunE: func(cmd *Command, args []string) error {
@@ -813,13 +817,13 @@ You will need to start a new shell for this setup to take effect.
 		},
 	}
 	if haveNoDescFlag {
-		fish.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		fish.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, Translate(compCmdNoDescFlagDesc))
 	}
 
 	powershell := &Command{
 		Use:   "powershell",
 		Short: fmt.Sprintf(shortDesc, "powershell"),
-		Long: fmt.Sprintf(`Generate the autocompletion script for powershell.
+		Long: fmt.Sprintf(Translate(`Generate the autocompletion script for powershell.
 
 To load completions in your current shell session:
 
@@ -827,7 +831,7 @@ To load completions in your current shell session:
 
 To load completions for every new session, add the output of the above command
 to your powershell profile.
-`, c.Root().Name()),
+`), c.Root().Name()),
 		Args:              NoArgs,
 		ValidArgsFunction: NoFileCompletions,
 		RunE: func(cmd *Command, args []string) error {
@@ -839,7 +843,7 @@ to your powershell profile.
 		},
 	}
 	if haveNoDescFlag {
-		powershell.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		powershell.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, Translate(compCmdNoDescFlagDesc))
 	}
 
 	completionCmd.AddCommand(bash, zsh, fish, powershell)
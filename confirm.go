@@ -0,0 +1,135 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/pflag"
+)
+
+const confirmFlagName = "yes"
+
+// ErrNotConfirmed is returned when the confirmation of a command is declined.
+var ErrNotConfirmed = errors.New("not confirmed")
+
+// ConfirmOptions are the options to control the confirmation asked before running a command.
+type ConfirmOptions struct {
+	// Message is the question asked before running the command. It is a template
+	// executed with a ConfirmData. No confirmation is asked when it is empty.
+	Message string
+	// TypeToConfirm, when set, requires the user to type its value, instead of
+	// answering y, to confirm. It is a template executed with a ConfirmData,
+	// such as "{{index .Args 0}}" to have the user type the name of the resource.
+	TypeToConfirm string
+}
+
+// ConfirmData is the data the templates of the ConfirmOptions are executed with.
+type ConfirmData struct {
+	Command *Command
+	Args    []string
+	// Flags are the values of the flags of the command, by name.
+	Flags map[string]string
+}
+
+// initConfirmFlag adds the --yes flag skipping the confirmation of c, if it asks for one.
+func (c *Command) initConfirmFlag() {
+	if c.Confirm.Message == "" {
+		return
+	}
+
+	c.mergePersistentFlags()
+	if c.Flags().Lookup(confirmFlagName) == nil {
+		usage := Translate("skip the confirmation prompt")
+		if c.Flags().ShorthandLookup("y") == nil {
+			c.Flags().BoolP(confirmFlagName, "y", false, usage)
+		} else {
+			c.Flags().Bool(confirmFlagName, false, usage)
+		}
+		_ = c.Flags().SetAnnotation(confirmFlagName, FlagSetByCobraAnnotation, []string{"true"})
+	}
+}
+
+// confirm asks for the confirmation of running c with args, unless --yes is given.
+// It fails when the input is not a terminal, so that c never runs unconfirmed.
+func (c *Command) confirm(p *prompter, args []string) error {
+	if c.Confirm.Message == "" {
+		return nil
+	}
+	if yes, err := c.Flags().GetBool(confirmFlagName); err == nil && yes {
+		return nil
+	}
+	if p == nil {
+		if !c.Streams().Interactive() {
+			return fmt.Errorf(Translate("%w: run %q with --%s to confirm it non-interactively"), ErrNotConfirmed, c.CommandPath(), confirmFlagName)
+		}
+		p = &prompter{cmd: c, in: c.InOrStdin()}
+	}
+
+	data := ConfirmData{Command: c, Args: args, Flags: map[string]string{}}
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		data.Flags[f.Name] = f.Value.String()
+	})
+	message, err := confirmText(c.Confirm.Message, data)
+	if err != nil {
+		return err
+	}
+	expected, err := confirmText(c.Confirm.TypeToConfirm, data)
+	if err != nil {
+		return err
+	}
+
+	out := c.ErrOrStderr()
+	if expected != "" {
+		fmt.Fprintln(out, message)
+		fmt.Fprintf(out, Translate("Type %q to confirm: "), expected)
+	} else {
+		fmt.Fprintf(out, "%s [y/N]: ", message)
+	}
+	answer, err := p.readLine(false)
+	if err != nil {
+		return ErrNotConfirmed
+	}
+	answer = strings.TrimSpace(answer)
+	if expected != "" && answer == expected {
+		return nil
+	}
+	if expected == "" && (strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")) {
+		return nil
+	}
+	return ErrNotConfirmed
+}
+
+// confirmText executes the confirmation template text with data.
+func confirmText(text string, data ConfirmData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	// The text comes from the command definition rather than a help template,
+	// so a parse error fails the command instead of panicking.
+	t, err := template.New("confirm").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func confirmTestCmd(ran *bool) *Command {
+	c := &Command{
+		Use:  "delete <name>",
+		Args: ExactArgs(1),
+		Confirm: ConfirmOptions{
+			Message: `Delete {{index .Args 0}}{{if eq (index .Flags "force") "true"}} forcibly{{end}}?`,
+		},
+		Run: func(*Command, []string) { *ran = true },
+	}
+	c.Flags().Bool("force", false, "force")
+	return c
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		input  string
+		ran    bool
+		prompt string
+	}{
+		{name: "Confirmed", args: []string{"db"}, input: "y\n", ran: true, prompt: "Delete db? [y/N]: "},
+		{name: "Confirmed with yes", args: []string{"db", "--force"}, input: "YES\n", ran: true, prompt: "Delete db forcibly? [y/N]: "},
+		{name: "Declined", args: []string{"db"}, input: "\n", ran: false, prompt: "Delete db? [y/N]: Error: not confirmed\n"},
+		{name: "End of input", args: []string{"db"}, input: "", ran: false, prompt: "Delete db? [y/N]: Error: not confirmed\n"},
+		{name: "Skipped with --yes", args: []string{"db", "--yes"}, input: "", ran: true, prompt: ""},
+		{name: "Skipped with -y", args: []string{"-y", "db"}, input: "", ran: true, prompt: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			c := confirmTestCmd(&ran)
+			c.SetStreams(terminalInput(tt.input))
+
+			output, err := executeCommand(c, tt.args...)
+			if tt.ran && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tt.ran && !errors.Is(err, ErrNotConfirmed) {
+				t.Errorf("Expected ErrNotConfirmed, got %v", err)
+			}
+			if ran != tt.ran {
+				t.Errorf("Expected ran to be %v", tt.ran)
+			}
+			if output != tt.prompt {
+				t.Errorf("Expected output %q, got %q", tt.prompt, output)
+			}
+		})
+	}
+}
+
+func TestConfirmBeforeHooks(t *testing.T) {
+	var hooks []string
+	ran := false
+	rootCmd := &Command{
+		Use:               "root",
+		PersistentPreRun:  func(*Command, []string) { hooks = append(hooks, "persistent pre-run") },
+		PersistentPostRun: func(*Command, []string) { hooks = append(hooks, "persistent post-run") },
+	}
+	c := confirmTestCmd(&ran)
+	c.PreRun = func(*Command, []string) { hooks = append(hooks, "pre-run") }
+	rootCmd.AddCommand(c)
+	rootCmd.SetStreams(terminalInput("n\n"))
+
+	if _, err := executeCommand(rootCmd, "delete", "db"); !errors.Is(err, ErrNotConfirmed) {
+		t.Errorf("Expected ErrNotConfirmed, got %v", err)
+	}
+	if ran || len(hooks) != 0 {
+		t.Errorf("Expected no hook to run before the confirmation, got %q", hooks)
+	}
+}
+
+func TestConfirmTypeToConfirm(t *testing.T) {
+	for input, expected := range map[string]bool{"db\n": true, "y\n": false, "d\n": false} {
+		ran := false
+		c := confirmTestCmd(&ran)
+		c.Confirm.TypeToConfirm = "{{index .Args 0}}"
+		c.SetStreams(terminalInput(input))
+
+		output, _ := executeCommand(c, "db")
+		if ran != expected {
+			t.Errorf("Input %q: expected ran to be %v", input, expected)
+		}
+		checkStringContains(t, output, "Delete db?\nType \"db\" to confirm: ")
+	}
+}
+
+func TestConfirmNotInteractive(t *testing.T) {
+	ran := false
+	c := confirmTestCmd(&ran)
+	c.SetIn(strings.NewReader("y\n"))
+
+	_, err := executeCommand(c, "db")
+	if !errors.Is(err, ErrNotConfirmed) {
+		t.Errorf("Expected ErrNotConfirmed, got %v", err)
+	}
+	if err.Error() != `not confirmed: run "delete" with --yes to confirm it non-interactively` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if ran {
+		t.Error("Expected the command not to run")
+	}
+
+	if _, err := executeCommand(c, "db", "--yes"); err != nil || !ran {
+		t.Errorf("Expected the command to run with --yes, got %v", err)
+	}
+}
+
+func TestConfirmFlagShorthandTaken(t *testing.T) {
+	ran := false
+	c := confirmTestCmd(&ran)
+	c.Flags().StringP("year", "y", "", "year")
+
+	if _, err := executeCommand(c, "db", "--yes", "-y", "2024"); err != nil || !ran {
+		t.Errorf("Expected the command to run, got %v", err)
+	}
+}
+
+func TestConfirmInvalidTemplate(t *testing.T) {
+	ran := false
+	c := confirmTestCmd(&ran)
+	c.Confirm.Message = "Delete {{index .Args 0}?"
+	c.SetStreams(terminalInput("y\n"))
+
+	_, err := executeCommand(c, "db")
+	if err == nil || !strings.Contains(err.Error(), "template: confirm") {
+		t.Errorf("Expected a template parse error, got %v", err)
+	}
+	if ran {
+		t.Error("Expected the command not to run")
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/json"
+	"io"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	helpFormatFlagName  = "format"
+	helpFormatFlagUsage = "output format of the help: text or json"
+	helpFormatText      = "text"
+	helpFormatJSON      = "json"
+)
+
+var helpFormats = []string{helpFormatText, helpFormatJSON}
+
+// DescriptionSchemaVersion is the version of the schema of TreeDescription.
+// It is incremented whenever a field is removed or changes meaning;
+// new fields may be added without changing it.
+const DescriptionSchemaVersion = 1
+
+// TreeDescription is a machine-readable description of a command tree,
+// as returned by Describe.
+type TreeDescription struct {
+	// SchemaVersion is the DescriptionSchemaVersion the description conforms to.
+	SchemaVersion int `json:"schema_version" yaml:"schema_version"`
+	// Command describes the command the description was made for, and its subcommands.
+	Command CommandDescription `json:"command" yaml:"command"`
+}
+
+// CommandDescription describes a command and its subcommands.
+type CommandDescription struct {
+	Name       string             `json:"name" yaml:"name"`
+	Path       string             `json:"path" yaml:"path"`
+	Use        string             `json:"use" yaml:"use"`
+	UseLine    string             `json:"use_line" yaml:"use_line"`
+	Aliases    []string           `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	SuggestFor []string           `json:"suggest_for,omitempty" yaml:"suggest_for,omitempty"`
+	Short      string             `json:"short,omitempty" yaml:"short,omitempty"`
+	Long       string             `json:"long,omitempty" yaml:"long,omitempty"`
+	Example    string             `json:"example,omitempty" yaml:"example,omitempty"`
+	GroupID    string             `json:"group_id,omitempty" yaml:"group_id,omitempty"`
+	Groups     []GroupDescription `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Version    string             `json:"version,omitempty" yaml:"version,omitempty"`
+	Deprecated string             `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Hidden     bool               `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	Runnable   bool               `json:"runnable" yaml:"runnable"`
+	// Handler names the functions a command loaded by LoadSpec is bound to in the
+	// SpecRegistry. If empty, the path of the command is used. Describe leaves it empty.
+	Handler     string            `json:"handler,omitempty" yaml:"handler,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Args        ArgsDescription   `json:"args" yaml:"args"`
+	// Flags are the flags declared on the command, including its persistent flags.
+	Flags []FlagDescription `json:"flags,omitempty" yaml:"flags,omitempty"`
+	// InheritedFlags are the persistent flags the command inherits from its parents.
+	InheritedFlags []FlagDescription    `json:"inherited_flags,omitempty" yaml:"inherited_flags,omitempty"`
+	Commands       []CommandDescription `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// GroupDescription describes a group of subcommands.
+type GroupDescription struct {
+	ID    string `json:"id" yaml:"id"`
+	Title string `json:"title" yaml:"title"`
+}
+
+// ArgsDescription describes the positional arguments a command accepts.
+type ArgsDescription struct {
+	// Validator is the name of the Cobra validator set as Args, such as "ExactArgs"
+	// or "NoArgs", "custom" for other validators, or empty if Args is not set.
+	Validator string `json:"validator,omitempty" yaml:"validator,omitempty"`
+	// ValidArgs are the arguments proposed by shell completion.
+	ValidArgs []string `json:"valid_args,omitempty" yaml:"valid_args,omitempty"`
+	// Min and Max are the smallest and largest numbers of arguments accepted by the
+	// validators provided by Cobra, as recorded when ExactArgs, RangeArgs and the like
+	// built them. Max is nil when the number is not bounded, and both are nil when
+	// the numbers are unknown, as they are for custom validators.
+	Min *int `json:"min,omitempty" yaml:"min,omitempty"`
+	Max *int `json:"max,omitempty" yaml:"max,omitempty"`
+	// ArgAliases are accepted but not proposed by shell completion.
+	ArgAliases []string `json:"arg_aliases,omitempty" yaml:"arg_aliases,omitempty"`
+	// Dynamic is true when the arguments are completed by ValidArgsFunction.
+	Dynamic bool `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
+}
+
+// FlagDescription describes a flag.
+type FlagDescription struct {
+	Name                string              `json:"name" yaml:"name"`
+	Shorthand           string              `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Usage               string              `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Type                string              `json:"type" yaml:"type"`
+	Default             string              `json:"default,omitempty" yaml:"default,omitempty"`
+	NoOptDefault        string              `json:"no_opt_default,omitempty" yaml:"no_opt_default,omitempty"`
+	Persistent          bool                `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+	Required            bool                `json:"required,omitempty" yaml:"required,omitempty"`
+	Negatable           bool                `json:"negatable,omitempty" yaml:"negatable,omitempty"`
+	Hidden              bool                `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	Deprecated          string              `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	ShorthandDeprecated string              `json:"shorthand_deprecated,omitempty" yaml:"shorthand_deprecated,omitempty"`
+	Annotations         map[string][]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// Describe returns a machine-readable description of c and all its subcommands,
+// including hidden and deprecated ones. No command is executed; like the documentation
+// generators, Describe adds the default help command and help flags to the tree.
+func (c *Command) Describe() *TreeDescription {
+	c.Root().InitDefaultHelpCmd()
+	return &TreeDescription{
+		SchemaVersion: DescriptionSchemaVersion,
+		Command:       c.describe(),
+	}
+}
+
+// writeDescriptionJSON writes the description of c as indented JSON to w.
+func (c *Command) writeDescriptionJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.Describe())
+}
+
+func (c *Command) describe() CommandDescription {
+	c.InitDefaultHelpFlag()
+
+	d := CommandDescription{
+		Name:        c.Name(),
+		Path:        c.CommandPath(),
+		Use:         c.Use,
+		UseLine:     c.UseLine(),
+		Aliases:     c.Aliases,
+		SuggestFor:  c.SuggestFor,
+		Short:       c.Short,
+		Long:        c.Long,
+		Example:     c.Example,
+		GroupID:     c.GroupID,
+		Version:     c.Version,
+		Deprecated:  c.Deprecated,
+		Hidden:      c.Hidden,
+		Runnable:    c.Runnable(),
+		Annotations: c.Annotations,
+		Args: ArgsDescription{
+			Validator:  argsValidatorName(c.Args),
+			ValidArgs:  c.ValidArgs,
+			ArgAliases: c.ArgAliases,
+			Dynamic:    c.ValidArgsFunction != nil,
+		},
+	}
+	if info, ok := lookupArgs(c.Args); ok && info.counted {
+		min, max := info.min, info.max
+		d.Args.Min = &min
+		if max >= 0 {
+			d.Args.Max = &max
+		}
+	}
+	for _, g := range c.Groups() {
+		d.Groups = append(d.Groups, GroupDescription{ID: g.ID, Title: g.Title})
+	}
+
+	persistent := c.PersistentFlags()
+	c.LocalFlags().VisitAll(func(f *flag.Flag) {
+		d.Flags = append(d.Flags, describeFlag(f, persistent.Lookup(f.Name) == f))
+	})
+	c.InheritedFlags().VisitAll(func(f *flag.Flag) {
+		d.InheritedFlags = append(d.InheritedFlags, describeFlag(f, true))
+	})
+
+	for _, sub := range c.Commands() {
+		d.Commands = append(d.Commands, sub.describe())
+	}
+	return d
+}
+
+func describeFlag(f *flag.Flag, persistent bool) FlagDescription {
+	required := false
+	if v, ok := f.Annotations[BashCompOneRequiredFlag]; ok && len(v) > 0 && v[0] == "true" {
+		required = true
+	}
+	return FlagDescription{
+		Name:                f.Name,
+		Shorthand:           f.Shorthand,
+		Usage:               f.Usage,
+		Type:                f.Value.Type(),
+		Default:             f.DefValue,
+		NoOptDefault:        f.NoOptDefVal,
+		Persistent:          persistent,
+		Required:            required,
+		Negatable:           IsFlagNegatable(f),
+		Hidden:              f.Hidden,
+		Deprecated:          f.Deprecated,
+		ShorthandDeprecated: f.ShorthandDeprecated,
+		Annotations:         f.Annotations,
+	}
+}
+
+// argsValidatorName returns the name of the Cobra validator args was built by,
+// "custom" if it was not built by Cobra, or an empty string if args is nil.
+func argsValidatorName(args PositionalArgs) string {
+	if args == nil {
+		return ""
+	}
+	if info, ok := lookupArgs(args); ok {
+		return info.name
+	}
+	return "custom"
+}
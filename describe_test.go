@@ -0,0 +1,228 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func setupDescribeTest() *Command {
+	rootCmd := &Command{Use: "root", Short: "the root", Version: "1.0.0", Run: emptyRun}
+	rootCmd.PersistentFlags().StringP("config", "c", "config.yaml", "config file")
+	rootCmd.AddGroup(&Group{ID: "manage", Title: "Management Commands:"})
+
+	childCmd := &Command{
+		Use:         "child <name>",
+		Aliases:     []string{"kid"},
+		Short:       "the child",
+		Long:        "the child command",
+		Example:     "root child foo",
+		GroupID:     "manage",
+		Annotations: map[string]string{"key": "value"},
+		Args:        ExactArgs(1),
+		ValidArgs:   []string{"foo", "bar"},
+		Run:         emptyRun,
+	}
+	childCmd.Flags().IntP("count", "n", 3, "number of times")
+	childCmd.Flags().Bool("force", false, "force it")
+	_ = childCmd.MarkFlagRequired("force")
+	childCmd.Flags().String("old", "", "old flag")
+	_ = childCmd.Flags().MarkDeprecated("old", "use --count")
+
+	hiddenCmd := &Command{Use: "hidden", Hidden: true, Args: NoArgs, Run: emptyRun}
+	customCmd := &Command{Use: "custom", Deprecated: "do not use", Args: func(*Command, []string) error { return nil }, Run: emptyRun}
+
+	rootCmd.AddCommand(childCmd, hiddenCmd, customCmd)
+	return rootCmd
+}
+
+func findDescription(d CommandDescription, name string) *CommandDescription {
+	for i := range d.Commands {
+		if d.Commands[i].Name == name {
+			return &d.Commands[i]
+		}
+	}
+	return nil
+}
+
+func findFlagDescription(flags []FlagDescription, name string) *FlagDescription {
+	for i := range flags {
+		if flags[i].Name == name {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+func TestDescribe(t *testing.T) {
+	rootCmd := setupDescribeTest()
+
+	d := rootCmd.Describe()
+	if d.SchemaVersion != DescriptionSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", DescriptionSchemaVersion, d.SchemaVersion)
+	}
+
+	root := d.Command
+	if root.Name != "root" || root.Version != "1.0.0" || !root.Runnable {
+		t.Errorf("Unexpected root description: %+v", root)
+	}
+	if !reflect.DeepEqual(root.Groups, []GroupDescription{{ID: "manage", Title: "Management Commands:"}}) {
+		t.Errorf("Unexpected groups: %+v", root.Groups)
+	}
+	config := findFlagDescription(root.Flags, "config")
+	if config == nil || !config.Persistent || config.Shorthand != "c" || config.Default != "config.yaml" || config.Type != "string" {
+		t.Errorf("Unexpected config flag: %+v", config)
+	}
+	if findFlagDescription(root.Flags, "help") == nil {
+		t.Errorf("Expected the help flag to be described")
+	}
+	if findDescription(root, "help") == nil {
+		t.Errorf("Expected the help command to be described")
+	}
+
+	child := findDescription(root, "child")
+	if child == nil {
+		t.Fatalf("Expected the child command to be described")
+	}
+	one := 1
+	expectedChild := CommandDescription{
+		Name:        "child",
+		Path:        "root child",
+		Use:         "child <name>",
+		UseLine:     "root child <name> [flags]",
+		Aliases:     []string{"kid"},
+		Short:       "the child",
+		Long:        "the child command",
+		Example:     "root child foo",
+		GroupID:     "manage",
+		Runnable:    true,
+		Annotations: map[string]string{"key": "value"},
+		Args:        ArgsDescription{Validator: "ExactArgs", Min: &one, Max: &one, ValidArgs: []string{"foo", "bar"}},
+	}
+	gotChild := *child
+	gotChild.Flags, gotChild.InheritedFlags = nil, nil
+	if !reflect.DeepEqual(gotChild, expectedChild) {
+		t.Errorf("Expected:\n%+v\nGot:\n%+v", expectedChild, gotChild)
+	}
+
+	count := findFlagDescription(child.Flags, "count")
+	if count == nil || count.Type != "int" || count.Default != "3" || count.Shorthand != "n" || count.Persistent {
+		t.Errorf("Unexpected count flag: %+v", count)
+	}
+	if force := findFlagDescription(child.Flags, "force"); force == nil || !force.Required || force.NoOptDefault != "true" {
+		t.Errorf("Unexpected force flag: %+v", force)
+	}
+	if old := findFlagDescription(child.Flags, "old"); old == nil || old.Deprecated != "use --count" {
+		t.Errorf("Unexpected old flag: %+v", old)
+	}
+	if config := findFlagDescription(child.InheritedFlags, "config"); config == nil || !config.Persistent {
+		t.Errorf("Expected the config flag to be inherited, got %+v", child.InheritedFlags)
+	}
+
+	if hidden := findDescription(root, "hidden"); hidden == nil || !hidden.Hidden || hidden.Args.Validator != "NoArgs" {
+		t.Errorf("Unexpected hidden command: %+v", hidden)
+	}
+	if custom := findDescription(root, "custom"); custom == nil || custom.Deprecated != "do not use" || custom.Args.Validator != "custom" {
+		t.Errorf("Unexpected custom command: %+v", custom)
+	}
+}
+
+func TestArgsValidatorName(t *testing.T) {
+	testcases := []struct {
+		args     PositionalArgs
+		expected string
+	}{
+		{nil, ""},
+		{NoArgs, "NoArgs"},
+		{ArbitraryArgs, "ArbitraryArgs"},
+		{OnlyValidArgs, "OnlyValidArgs"},
+		{MinimumNArgs(1), "MinimumNArgs"},
+		{MaximumNArgs(1), "MaximumNArgs"},
+		{ExactArgs(1), "ExactArgs"},
+		{RangeArgs(1, 2), "RangeArgs"},
+		{MatchAll(NoArgs), "MatchAll"},
+		{func(*Command, []string) error { return nil }, "custom"},
+	}
+
+	for _, tc := range testcases {
+		if got := argsValidatorName(tc.args); got != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestDescribeArgsCounts(t *testing.T) {
+	custom := func(cmd *Command, args []string) error {
+		t.Error("Expected Describe not to call the Args validator")
+		return nil
+	}
+	testcases := []struct {
+		args     PositionalArgs
+		expected string
+	}{
+		{NoArgs, `{"validator":"NoArgs","min":0,"max":0}`},
+		{ArbitraryArgs, `{"validator":"ArbitraryArgs","min":0}`},
+		{ExactArgs(2), `{"validator":"ExactArgs","min":2,"max":2}`},
+		{ExactArgs(0), `{"validator":"ExactArgs","min":0,"max":0}`},
+		{MinimumNArgs(3), `{"validator":"MinimumNArgs","min":3}`},
+		{MaximumNArgs(4), `{"validator":"MaximumNArgs","min":0,"max":4}`},
+		{RangeArgs(1, 5), `{"validator":"RangeArgs","min":1,"max":5}`},
+		{MatchAll(RangeArgs(1, 5), MinimumNArgs(2), OnlyValidArgs), `{"validator":"MatchAll","min":2,"max":5}`},
+		{MatchAll(ExactArgs(1), custom), `{"validator":"MatchAll"}`},
+		{custom, `{"validator":"custom"}`},
+	}
+
+	for _, tc := range testcases {
+		c := &Command{Use: "c", Args: tc.args}
+		output, err := json.Marshal(c.Describe().Command.Args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(output) != tc.expected {
+			t.Errorf("Expected %s, got %s", tc.expected, output)
+		}
+	}
+}
+
+func TestHelpFormatJSON(t *testing.T) {
+	rootCmd := setupDescribeTest()
+
+	output, err := executeCommand(rootCmd, "help", "--format=json", "child")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	var d TreeDescription
+	if err := json.Unmarshal([]byte(output), &d); err != nil {
+		t.Fatalf("Expected valid JSON, got %v:\n%s", err, output)
+	}
+	if d.SchemaVersion != DescriptionSchemaVersion || d.Command.Path != "root child" {
+		t.Errorf("Unexpected description: %+v", d)
+	}
+	checkStringContains(t, output, `"schema_version": 1`)
+	checkStringContains(t, output, `"validator": "ExactArgs"`)
+}
+
+func TestHelpFormatUnsupported(t *testing.T) {
+	rootCmd := setupDescribeTest()
+
+	output, err := executeCommand(rootCmd, "help", "--format=xml", "child")
+	if err == nil {
+		t.Fatalf("Expected an error for an unsupported format, got output:\n%s", output)
+	}
+	checkStringContains(t, err.Error(), `unsupported help format "xml", must be one of: text, json`)
+}
@@ -0,0 +1,165 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func printOptionsAsciidoc(buf *bytes.Buffer, d *CommandDoc) {
+	if opts := visibleOptions(d.Options); len(opts) > 0 {
+		buf.WriteString("== Options\n\n")
+		printFlagTableAsciidoc(buf, opts, false)
+	}
+
+	if opts := visibleOptions(d.InheritedOptions); len(opts) > 0 {
+		buf.WriteString("== Options inherited from parent commands\n\n")
+		printFlagTableAsciidoc(buf, opts, true)
+	}
+}
+
+// printFlagTableAsciidoc writes the options as a four column table.
+func printFlagTableAsciidoc(buf *bytes.Buffer, opts []OptionDoc, inherited bool) {
+	buf.WriteString("[cols=\"2,1,1,4\",options=\"header\"]\n")
+	buf.WriteString("|===\n")
+	buf.WriteString("|Flag |Type |Default |Description\n")
+	for _, o := range opts {
+		def := o.displayDefault()
+		if def != "" {
+			def = "`" + escapeAsciidocCell(def) + "`"
+		}
+		buf.WriteString("\n")
+		buf.WriteString("|`" + o.displayFlag() + "`\n")
+		buf.WriteString("|" + o.Type + "\n")
+		buf.WriteString("|" + def + "\n")
+		buf.WriteString("|" + escapeAsciidocCell(o.description(inherited)) + "\n")
+	}
+	buf.WriteString("|===\n\n")
+}
+
+// escapeAsciidocCell escapes the cell separator so that text containing
+// a pipe does not split the table row.
+func escapeAsciidocCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// defaultAsciidocLinkHandler for default AsciiDoc xref markup
+func defaultAsciidocLinkHandler(name, ref string) string {
+	return fmt.Sprintf("xref:%s.adoc[%s]", ref, name)
+}
+
+// GenAsciidoc creates AsciiDoc output.
+func GenAsciidoc(cmd *cobra.Command, w io.Writer) error {
+	return GenAsciidocCustom(cmd, w, defaultAsciidocLinkHandler)
+}
+
+// GenAsciidocCustom creates custom AsciiDoc output.
+func GenAsciidocCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string) error {
+	d := NewCommandDoc(cmd)
+	buf := new(bytes.Buffer)
+
+	short := d.Synopsis
+	long := d.Description
+	if len(long) == 0 {
+		long = short
+	}
+
+	buf.WriteString("[[" + strings.ReplaceAll(d.Name, " ", "_") + "]]\n")
+	buf.WriteString("= " + d.Name + "\n\n")
+	buf.WriteString(short + "\n\n")
+	buf.WriteString("== Synopsis\n\n")
+	buf.WriteString(long + "\n\n")
+
+	if len(d.Usage) > 0 {
+		buf.WriteString(fmt.Sprintf("[source,shell]\n----\n%s\n----\n\n", d.Usage))
+	}
+
+	if props := d.properties(); len(props) > 0 {
+		for _, p := range props {
+			buf.WriteString(fmt.Sprintf("%s:: %s\n", p.Label, p.Value))
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(d.Example) > 0 {
+		buf.WriteString("== Examples\n\n")
+		buf.WriteString(fmt.Sprintf("[source,shell]\n----\n%s\n----\n\n", strings.TrimRight(d.Example, "\n")))
+	}
+
+	printOptionsAsciidoc(buf, d)
+
+	if len(d.SeeAlso) > 0 {
+		buf.WriteString("== SEE ALSO\n\n")
+		for _, s := range d.SeeAlso {
+			buf.WriteString(fmt.Sprintf("* %s - %s\n", linkHandler(s.Name, s.Ref), s.Short))
+		}
+		buf.WriteString("\n")
+		cmd.VisitParents(func(c *cobra.Command) {
+			if c.DisableAutoGenTag {
+				cmd.DisableAutoGenTag = c.DisableAutoGenTag
+			}
+		})
+	}
+	if !cmd.DisableAutoGenTag {
+		buf.WriteString("_Auto generated by spf13/cobra on " + time.Now().Format("2-Jan-2006") + "_\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// GenAsciidocTree will generate an AsciiDoc page for this command and all
+// descendants in the directory given.
+// This function may not work correctly if your command names have `-` in them.
+// If you have `cmd` with two subcmds, `sub` and `sub-third`,
+// and `sub` has a subcommand called `third`, it is undefined which
+// help output will be in the file `cmd-sub-third.adoc`.
+func GenAsciidocTree(cmd *cobra.Command, dir string) error {
+	emptyStr := func(s string) string { return "" }
+	return GenAsciidocTreeCustom(cmd, dir, emptyStr, defaultAsciidocLinkHandler)
+}
+
+// GenAsciidocTreeCustom is the same as GenAsciidocTree, but
+// with custom filePrepender and linkHandler.
+func GenAsciidocTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string) string, linkHandler func(string, string) string) error {
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		if err := GenAsciidocTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
+			return err
+		}
+	}
+
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".adoc"
+	filename := filepath.Join(dir, basename)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
+		return err
+	}
+	return GenAsciidocCustom(cmd, f, linkHandler)
+}
@@ -0,0 +1,103 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGenAsciidoc(t *testing.T) {
+	// We generate on a subcommand so we have both subcommands and parents
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(echoCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "[[root_echo]]\n= root echo\n")
+	checkStringContains(t, output, echoCmd.Long)
+	checkStringContains(t, output, "== Examples\n\n[source,shell]\n----\n"+echoCmd.Example+"\n----\n")
+	checkStringContains(t, output, "|`-b, --boolone`\n|bool\n|`true`\n|help message for flag boolone\n")
+	checkStringContains(t, output, "|`-i, --intone`\n|int\n|`123`\n")
+	checkStringContains(t, output, "== Options inherited from parent commands")
+	checkStringContains(t, output, "rootflag")
+	checkStringContains(t, output, "* xref:root.adoc[root] - "+rootCmd.Short)
+	checkStringContains(t, output, "* xref:root_echo_echosub.adoc[root echo echosub] - "+echoSubCmd.Short)
+	checkStringOmits(t, output, deprecatedCmd.Short)
+}
+
+func TestGenAsciidocEscapesTableCells(t *testing.T) {
+	c := &cobra.Command{Use: "pipe", Run: emptyRun}
+	c.Flags().String("sep", "|", "field separator, e.g. a|b")
+
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "|`--sep`\n|string\n|`\\|`\n|field separator, e.g. a\\|b\n")
+}
+
+func TestGenAsciidocCustomLinkHandler(t *testing.T) {
+	linkHandler := func(name, ref string) string {
+		return "<<" + ref + "," + name + ">>"
+	}
+	buf := new(bytes.Buffer)
+	if err := GenAsciidocCustom(echoCmd, buf, linkHandler); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "* <<root,root>> - "+rootCmd.Short)
+	checkStringOmits(t, output, "xref:")
+}
+
+func TestGenAsciidocNoTag(t *testing.T) {
+	rootCmd.DisableAutoGenTag = true
+	defer func() { rootCmd.DisableAutoGenTag = false }()
+
+	buf := new(bytes.Buffer)
+	if err := GenAsciidoc(rootCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringOmits(t, output, "Auto generated")
+}
+
+func TestGenAsciidocTree(t *testing.T) {
+	c := &cobra.Command{Use: "do [OPTIONS] arg1 arg2"}
+
+	tmpdir, err := ioutil.TempDir("", "test-gen-asciidoc-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %s", err.Error())
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := GenAsciidocTree(c, tmpdir); err != nil {
+		t.Fatalf("GenAsciidocTree failed: %s", err.Error())
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpdir, "do.adoc")); err != nil {
+		t.Fatalf("Expected file 'do.adoc' to exist")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const helpExtension = ".txt"
+
+// GenHelpTree writes the help output of cmd and all its subcommands,
+// including the additional help topics, to one file per command in dir,
+// such as root_sub.txt. The help is rendered through the HelpFunc and
+// templates of each command, exactly as --help prints it when the output
+// is not a terminal: the default commands and flags Execute adds are added
+// first. Hidden and deprecated commands are skipped.
+func GenHelpTree(cmd *cobra.Command, dir string) error {
+	cmd.InitDefaults()
+	for _, c := range cmd.Commands() {
+		if !hasHelp(c) {
+			continue
+		}
+		if err := GenHelpTree(c, dir); err != nil {
+			return err
+		}
+	}
+
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_") + helpExtension
+	filename := filepath.Join(dir, basename)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.WriteString(f, cmd.HelpString())
+	return err
+}
+
+// WriteHelpTree writes the help output of cmd and all its subcommands to w,
+// each preceded by a "==> command path <==" separator line. See GenHelpTree.
+func WriteHelpTree(cmd *cobra.Command, w io.Writer) error {
+	cmd.InitDefaults()
+	if _, err := fmt.Fprintf(w, "==> %s <==\n%s", cmd.CommandPath(), cmd.HelpString()); err != nil {
+		return err
+	}
+	for _, c := range cmd.Commands() {
+		if !hasHelp(c) {
+			continue
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		if err := WriteHelpTree(c, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasHelp returns whether the help of c is part of the help tree: c is
+// available or is an additional help topic.
+func hasHelp(c *cobra.Command) bool {
+	return c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand()
+}
@@ -0,0 +1,129 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWriteHelpTree(t *testing.T) {
+	// Keep the default completion command out of the shared command tree.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	defer func() { rootCmd.CompletionOptions.DisableDefaultCmd = false }()
+
+	buf := new(bytes.Buffer)
+	if err := WriteHelpTree(rootCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "==> root <==\n"+rootCmd.Long)
+	checkStringContains(t, output, "\n\n==> root echo <==\n"+echoCmd.Long)
+	checkStringContains(t, output, "==> root echo times <==\n")
+	checkStringContains(t, output, "help message for child flag strtwo")
+	checkStringOmits(t, output, "==> root echo deprecated <==")
+	checkStringContains(t, output, echoCmd.HelpString())
+}
+
+func TestWriteHelpTreeMatchesHelpFlag(t *testing.T) {
+	newTree := func() *cobra.Command {
+		root := &cobra.Command{Use: "root", Short: "The root", Version: "1.0", Run: emptyRun}
+		sub := &cobra.Command{Use: "sub", Short: "A subcommand", Run: emptyRun}
+		sub.Flags().String("name", "", "the name")
+		root.AddCommand(sub)
+		return root
+	}
+
+	for _, args := range [][]string{{"--help"}, {"sub", "--help"}} {
+		root := newTree()
+		out := new(bytes.Buffer)
+		root.SetOut(out)
+		root.SetErr(out)
+		root.SetArgs(args)
+		if err := root.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		root = newTree()
+		buf := new(bytes.Buffer)
+		if err := WriteHelpTree(root, buf); err != nil {
+			t.Fatal(err)
+		}
+		path := "root"
+		if args[0] == "sub" {
+			path = "root sub"
+		}
+		checkStringContains(t, buf.String(), "==> "+path+" <==\n"+out.String())
+	}
+}
+
+func TestWriteHelpTreeHelpTopics(t *testing.T) {
+	c := &cobra.Command{Use: "c", Run: emptyRun, CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true}}
+	c.AddCommand(&cobra.Command{Use: "environment", Short: "Environment variables", Long: "The environment variables read by c."})
+	c.AddCommand(&cobra.Command{Use: "hidden", Long: "Not shown", Hidden: true})
+
+	buf := new(bytes.Buffer)
+	if err := WriteHelpTree(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, buf.String(), "==> c environment <==\nThe environment variables read by c.\n")
+	checkStringOmits(t, buf.String(), "==> c hidden <==")
+}
+
+func TestWriteHelpTreeCustomHelpFunc(t *testing.T) {
+	c := &cobra.Command{Use: "c", Run: emptyRun, CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true}}
+	c.AddCommand(&cobra.Command{Use: "sub", Run: emptyRun})
+	c.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		cmd.Printf("custom help of %s\n", cmd.Name())
+	})
+
+	buf := new(bytes.Buffer)
+	if err := WriteHelpTree(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "==> c <==\ncustom help of c\n\n==> c sub <==\ncustom help of sub\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestGenHelpTree(t *testing.T) {
+	c := &cobra.Command{Use: "do [OPTIONS] arg1 arg2", Long: "do things"}
+	c.AddCommand(&cobra.Command{Use: "sub", Run: emptyRun})
+	tmpdir, err := ioutil.TempDir("", "test-gen-help-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := GenHelpTree(c, tmpdir); err != nil {
+		t.Fatalf("GenHelpTree failed: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(tmpdir, "do.txt"))
+	if err != nil {
+		t.Fatalf("Expected file 'do.txt' to exist")
+	}
+	checkStringContains(t, string(content), "do things")
+	if _, err := os.Stat(filepath.Join(tmpdir, "do_sub.txt")); err != nil {
+		t.Fatalf("Expected file 'do_sub.txt' to exist")
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	htmlExtension       = ".html"
+	htmlIndexFile       = "index.html"
+	htmlStyleFile       = "style.css"
+	htmlSearchIndexFile = "search.json"
+	htmlSearchFile      = "search.js"
+)
+
+// GenHTMLTreeOptions is the options for generating the HTML site.
+// Used only in GenHTMLTreeFromOpts.
+type GenHTMLTreeOptions struct {
+	// Path is the directory the site is written to.
+	Path string
+	// Title is the title of the site. It defaults to the path of the command.
+	Title string
+	// IndexTemplate replaces the html/template of the index page, which is
+	// executed with an *HTMLSite.
+	IndexTemplate string
+	// CommandTemplate replaces the html/template of the command pages, which is
+	// executed with an *HTMLCommand.
+	CommandTemplate string
+	// CSS replaces the style sheet of the site, written to style.css.
+	CSS string
+}
+
+// HTMLSite is the data of the index page of the HTML site.
+type HTMLSite struct {
+	Title string
+	Root  *HTMLCommand
+}
+
+// HTMLCommand is the data of the page of a command in the HTML site.
+type HTMLCommand struct {
+	Site           *HTMLSite
+	Path           string
+	Name           string
+	Short          string
+	Long           string
+	Example        string
+	UseLine        string
+	File           string
+	Parent         *HTMLCommand
+	Children       []*HTMLCommand
+	Flags          []HTMLFlag
+	InheritedFlags []HTMLFlag
+	// Properties are the aliases, deprecation, group, valid arguments and
+	// annotations of the command.
+	Properties []HTMLProperty
+	// Doc is the document model of the command.
+	Doc *CommandDoc
+}
+
+// HTMLProperty is a labelled piece of metadata on the page of a command.
+type HTMLProperty struct {
+	Label string
+	Value string
+}
+
+// HTMLFlag describes a flag on the page of a command.
+type HTMLFlag struct {
+	Name      string
+	Shorthand string
+	Type      string
+	// Default is the default value, empty if it is the zero value.
+	Default string
+	Usage   string
+	// Notes tells whether the flag is persistent or required, and whether its
+	// shorthand is deprecated.
+	Notes string
+	// Anchor is the id of the flag in the page of the command.
+	Anchor string
+}
+
+// htmlSearchEntry is an entry of the search index of the HTML site.
+type htmlSearchEntry struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// GenHTMLTree generates a static HTML site documenting cmd and all its
+// subcommands in dir: an index of the command tree, a page per command, a
+// style sheet and a JSON search index of the commands and flags.
+func GenHTMLTree(cmd *cobra.Command, dir string) error {
+	return GenHTMLTreeFromOpts(cmd, GenHTMLTreeOptions{Path: dir})
+}
+
+// GenHTMLTreeFromOpts generates the HTML site of cmd with the given options.
+func GenHTMLTreeFromOpts(cmd *cobra.Command, opts GenHTMLTreeOptions) error {
+	site := &HTMLSite{Title: opts.Title}
+	if site.Title == "" {
+		site.Title = cmd.CommandPath()
+	}
+	site.Root = newHTMLCommand(site, cmd, nil)
+
+	indexTemplate, err := htmlTemplate("index", opts.IndexTemplate, defaultHTMLIndexTemplate)
+	if err != nil {
+		return err
+	}
+	commandTemplate, err := htmlTemplate("command", opts.CommandTemplate, defaultHTMLCommandTemplate)
+	if err != nil {
+		return err
+	}
+
+	if err := writeHTMLFile(filepath.Join(opts.Path, htmlIndexFile), func(w io.Writer) error {
+		return indexTemplate.Execute(w, site)
+	}); err != nil {
+		return err
+	}
+	var search []htmlSearchEntry
+	var genPages func(c *HTMLCommand) error
+	genPages = func(c *HTMLCommand) error {
+		search = append(search, htmlSearchEntry{Kind: "command", Name: c.Path, Description: c.Short, URL: c.File})
+		for _, f := range c.Flags {
+			search = append(search, htmlSearchEntry{Kind: "flag", Name: c.Path + " --" + f.Name, Description: f.Usage, URL: c.File + "#" + f.Anchor})
+		}
+		if err := writeHTMLFile(filepath.Join(opts.Path, c.File), func(w io.Writer) error {
+			return commandTemplate.Execute(w, c)
+		}); err != nil {
+			return err
+		}
+		for _, child := range c.Children {
+			if err := genPages(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := genPages(site.Root); err != nil {
+		return err
+	}
+
+	if err := writeHTMLFile(filepath.Join(opts.Path, htmlSearchIndexFile), func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(search)
+	}); err != nil {
+		return err
+	}
+	if err := writeHTMLFile(filepath.Join(opts.Path, htmlSearchFile), func(w io.Writer) error {
+		_, err := io.WriteString(w, htmlSearchScript)
+		return err
+	}); err != nil {
+		return err
+	}
+	css := opts.CSS
+	if css == "" {
+		css = defaultHTMLStyle
+	}
+	return writeHTMLFile(filepath.Join(opts.Path, htmlStyleFile), func(w io.Writer) error {
+		_, err := io.WriteString(w, css)
+		return err
+	})
+}
+
+func newHTMLCommand(site *HTMLSite, cmd *cobra.Command, parent *HTMLCommand) *HTMLCommand {
+	d := NewCommandDoc(cmd)
+	c := &HTMLCommand{
+		Site:           site,
+		Path:           d.Name,
+		Name:           cmd.Name(),
+		Short:          d.Synopsis,
+		Long:           d.Description,
+		Example:        d.Example,
+		UseLine:        cmd.UseLine(),
+		File:           strings.ReplaceAll(d.Name, " ", "_") + htmlExtension,
+		Parent:         parent,
+		Flags:          htmlFlags(d.Options, false),
+		InheritedFlags: htmlFlags(d.InheritedOptions, true),
+		Doc:            d,
+	}
+	for _, p := range d.properties() {
+		c.Properties = append(c.Properties, HTMLProperty{Label: p.Label, Value: p.Value})
+	}
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() || sub.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		c.Children = append(c.Children, newHTMLCommand(site, sub, c))
+	}
+	return c
+}
+
+func htmlFlags(opts []OptionDoc, inherited bool) []HTMLFlag {
+	var result []HTMLFlag
+	for _, o := range visibleOptions(opts) {
+		name := o.Name
+		if o.Negatable {
+			name = "[no-]" + name
+		}
+		shorthand := o.Shorthand
+		if len(o.ShorthandDeprecated) > 0 {
+			shorthand = ""
+		}
+		result = append(result, HTMLFlag{
+			Name:      name,
+			Shorthand: shorthand,
+			Type:      o.Type,
+			Default:   o.displayDefault(),
+			Usage:     o.Usage,
+			Notes:     strings.Join(o.notes(inherited), "; "),
+			Anchor:    "flag-" + o.Name,
+		})
+	}
+	return result
+}
+
+func htmlTemplate(name, text, def string) (*template.Template, error) {
+	if text == "" {
+		text = def
+	}
+	return template.New(name).Parse(text)
+}
+
+func writeHTMLFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return write(f)
+}
+
+const defaultHTMLIndexTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<header><a href="index.html">{{.Title}}</a></header>
+<main>
+<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="Search commands and flags" autocomplete="off">
+<ul id="search-results"></ul>
+<h2>Commands</h2>
+{{define "tree"}}<li><a href="{{.File}}">{{.Path}}</a>{{with .Short}} - {{.}}{{end}}{{if .Children}}
+<ul>
+{{range .Children}}{{template "tree" .}}
+{{end}}</ul>{{end}}</li>{{end}}<ul class="tree">
+{{template "tree" .Root}}
+</ul>
+</main>
+<script src="search.js"></script>
+</body>
+</html>
+`
+
+const defaultHTMLCommandTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Path}} - {{.Site.Title}}</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<header><a href="index.html">{{.Site.Title}}</a>{{define "breadcrumbs"}}{{with .Parent}}{{template "breadcrumbs" .}} / <a href="{{.File}}">{{.Name}}</a>{{end}}{{end}}{{template "breadcrumbs" .}} / {{.Name}}</header>
+<main>
+<h1>{{.Path}}</h1>
+{{with .Short}}<p class="short">{{.}}</p>
+{{end}}<h2>Synopsis</h2>
+{{with .Long}}<p class="long">{{.}}</p>
+{{end}}<pre><code>{{.UseLine}}</code></pre>
+{{with .Properties}}<dl class="properties">
+{{range .}}<dt>{{.Label}}</dt>
+<dd>{{.Value}}</dd>
+{{end}}</dl>
+{{end}}{{with .Example}}<h2>Examples</h2>
+<pre><code>{{.}}</code></pre>
+{{end}}{{define "flags"}}<dl class="flags">
+{{range .}}<dt id="{{.Anchor}}"><a href="#{{.Anchor}}">{{with .Shorthand}}-{{.}}, {{end}}--{{.Name}}</a> <span class="type">{{.Type}}</span>{{with .Default}} <span class="default">(default {{.}})</span>{{end}}</dt>
+<dd>{{.Usage}}{{with .Notes}} <span class="notes">({{.}})</span>{{end}}</dd>
+{{end}}</dl>
+{{end}}{{with .Flags}}<h2>Options</h2>
+{{template "flags" .}}{{end}}{{with .InheritedFlags}}<h2>Options inherited from parent commands</h2>
+{{template "flags" .}}{{end}}<h2>See also</h2>
+<ul class="see-also">
+{{with .Parent}}<li><a href="{{.File}}">{{.Path}}</a>{{with .Short}} - {{.}}{{end}}</li>
+{{end}}{{range .Children}}<li><a href="{{.File}}">{{.Path}}</a>{{with .Short}} - {{.}}{{end}}</li>
+{{end}}</ul>
+</main>
+</body>
+</html>
+`
+
+const defaultHTMLStyle = `body {
+  margin: 0;
+  font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif;
+  line-height: 1.5;
+  color: #24292f;
+}
+header {
+  padding: 0.75em 2em;
+  background: #24292f;
+  color: #fff;
+}
+header a {
+  color: #fff;
+}
+main {
+  max-width: 60em;
+  padding: 1em 2em;
+}
+a {
+  color: #0969da;
+  text-decoration: none;
+}
+a:hover {
+  text-decoration: underline;
+}
+pre {
+  padding: 1em;
+  overflow: auto;
+  background: #f6f8fa;
+}
+.long {
+  white-space: pre-line;
+}
+.flags dt {
+  font-family: monospace;
+}
+.flags dd {
+  margin: 0 0 0.75em 2em;
+}
+.properties dt {
+  font-weight: bold;
+}
+.properties dd {
+  margin: 0 0 0.5em 2em;
+}
+.type, .default, .notes {
+  color: #57606a;
+}
+#search {
+  width: 100%;
+  padding: 0.5em;
+  font-size: 1em;
+}
+`
+
+const htmlSearchScript = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  if (!input || !results) {
+    return;
+  }
+  fetch("search.json").then(function (response) {
+    return response.json();
+  }).then(function (index) {
+    input.addEventListener("input", function () {
+      var query = input.value.toLowerCase();
+      results.innerHTML = "";
+      if (!query) {
+        return;
+      }
+      index.forEach(function (entry) {
+        if ((entry.name + " " + entry.description).toLowerCase().indexOf(query) < 0) {
+          return;
+        }
+        var item = document.createElement("li");
+        var link = document.createElement("a");
+        link.href = entry.url;
+        link.textContent = entry.name;
+        item.appendChild(link);
+        item.appendChild(document.createTextNode(" " + entry.description));
+        results.appendChild(item);
+      });
+    });
+  });
+})();
+`
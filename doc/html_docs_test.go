@@ -0,0 +1,117 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func genHTMLTestSite(t *testing.T, opts GenHTMLTreeOptions) string {
+	tmpdir, err := ioutil.TempDir("", "test-gen-html-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpdir) })
+
+	opts.Path = tmpdir
+	if err := GenHTMLTreeFromOpts(rootCmd, opts); err != nil {
+		t.Fatalf("GenHTMLTreeFromOpts failed: %v", err)
+	}
+	return tmpdir
+}
+
+func readHTMLTestFile(t *testing.T, dir, name string) string {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("Expected file %q to exist: %v", name, err)
+	}
+	return string(content)
+}
+
+func TestGenHTMLTree(t *testing.T) {
+	dir := genHTMLTestSite(t, GenHTMLTreeOptions{})
+
+	index := readHTMLTestFile(t, dir, "index.html")
+	checkStringContains(t, index, "<title>root</title>")
+	checkStringContains(t, index, `<a href="root_echo.html">root echo</a> - Echo anything to the screen`)
+	checkStringContains(t, index, `<a href="root_echo_times.html">root echo times</a>`)
+	checkStringContains(t, index, `<script src="search.js"></script>`)
+	checkStringOmits(t, index, "root_echo_deprecated.html")
+
+	page := readHTMLTestFile(t, dir, "root_echo.html")
+	checkStringContains(t, page, "<h1>root echo</h1>")
+	checkStringContains(t, page, `<p class="long">an utterly useless command for testing</p>`)
+	checkStringContains(t, page, `<dt id="flag-boolone"><a href="#flag-boolone">-b, --boolone</a>`)
+	checkStringContains(t, page, `<dt id="flag-rootflag">`)
+	checkStringContains(t, page, "Options inherited from parent commands")
+	checkStringContains(t, page, `<li><a href="root.html">root</a> - Root short description</li>`)
+	checkStringContains(t, page, `<li><a href="root_echo_echosub.html">root echo echosub</a>`)
+	checkStringContains(t, page, `<a href="index.html">root</a> / <a href="root.html">root</a> / echo</header>`)
+
+	for _, name := range []string{"root.html", "root_echo_echosub.html", "style.css", "search.js"} {
+		readHTMLTestFile(t, dir, name)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "root_echo_deprecated.html")); err == nil {
+		t.Errorf("Expected no page for the deprecated command")
+	}
+}
+
+func TestGenHTMLTreeSearchIndex(t *testing.T) {
+	dir := genHTMLTestSite(t, GenHTMLTreeOptions{})
+
+	var entries []htmlSearchEntry
+	if err := json.Unmarshal([]byte(readHTMLTestFile(t, dir, "search.json")), &entries); err != nil {
+		t.Fatalf("Invalid search index: %v", err)
+	}
+	found := map[string]htmlSearchEntry{}
+	for _, entry := range entries {
+		found[entry.Name] = entry
+	}
+	if entry := found["root echo times"]; entry.Kind != "command" || entry.URL != "root_echo_times.html" {
+		t.Errorf("Expected the times command in the search index, got %+v", entry)
+	}
+	if entry := found["root echo --intone"]; entry.Kind != "flag" || entry.URL != "root_echo.html#flag-intone" || entry.Description != "help message for flag intone" {
+		t.Errorf("Expected the intone flag in the search index, got %+v", entry)
+	}
+}
+
+func TestGenHTMLTreeCustom(t *testing.T) {
+	dir := genHTMLTestSite(t, GenHTMLTreeOptions{
+		Title:           "Reference",
+		IndexTemplate:   `{{.Title}}: {{.Root.Path}}`,
+		CommandTemplate: `{{.Path}} in {{.Site.Title}}`,
+		CSS:             "body { color: red; }",
+	})
+
+	if index := readHTMLTestFile(t, dir, "index.html"); index != "Reference: root" {
+		t.Errorf("Expected the custom index template, got %q", index)
+	}
+	if page := readHTMLTestFile(t, dir, "root_echo.html"); page != "root echo in Reference" {
+		t.Errorf("Expected the custom command template, got %q", page)
+	}
+	if css := readHTMLTestFile(t, dir, "style.css"); css != "body { color: red; }" {
+		t.Errorf("Expected the custom style sheet, got %q", css)
+	}
+}
+
+func TestGenHTMLTreeInvalidTemplate(t *testing.T) {
+	if err := GenHTMLTreeFromOpts(rootCmd, GenHTMLTreeOptions{Path: os.TempDir(), IndexTemplate: "{{"}); err == nil {
+		t.Errorf("Expected an error for an invalid template")
+	}
+}
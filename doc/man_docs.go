@@ -16,11 +16,11 @@ package doc
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -98,6 +98,106 @@ type GenManHeader struct {
 	date    string
 	Source  string
 	Manual  string
+
+	// ManSections are the sections of the man pages of all the commands,
+	// to which the sections set on each command with SetManSections are added.
+	ManSections
+}
+
+// ManSectionsAnnotation is the annotation of a command holding its man page
+// sections, as set by SetManSections.
+const ManSectionsAnnotation = "cobra_annotation_man_sections"
+
+// ManEntry is an entry of a man page section, such as an environment variable.
+type ManEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ManSections are the standard man page sections which are not derived from
+// the definition of a command.
+type ManSections struct {
+	// Environment describes the environment variables read by the command.
+	Environment []ManEntry `json:"environment,omitempty"`
+	// ExitStatus describes the exit statuses of the command, such as "0".
+	ExitStatus []ManEntry `json:"exit_status,omitempty"`
+	// Files describes the files used by the command.
+	Files []ManEntry `json:"files,omitempty"`
+	// Authors are the authors of the command.
+	Authors []string `json:"authors,omitempty"`
+	// Bugs tells how to report bugs.
+	Bugs string `json:"bugs,omitempty"`
+}
+
+// SetManSections sets the man page sections of cmd. They are added to the sections
+// of the header and of the parents of cmd in its man page and in those of its
+// subcommands; entries with the name of an entry of a parent replace it.
+func SetManSections(cmd *cobra.Command, sections *ManSections) error {
+	b, err := json.Marshal(sections)
+	if err != nil {
+		return err
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[ManSectionsAnnotation] = string(b)
+	return nil
+}
+
+// manSections returns the sections of the man page of cmd: those of the header,
+// merged with those set on cmd and its parents.
+func manSections(cmd *cobra.Command, header *GenManHeader) (*ManSections, error) {
+	var commands []*cobra.Command
+	for c := cmd; c != nil; c = c.Parent() {
+		commands = append([]*cobra.Command{c}, commands...)
+	}
+
+	merged := header.ManSections
+	merged.Authors = append([]string{}, merged.Authors...)
+	for _, c := range commands {
+		value, ok := c.Annotations[ManSectionsAnnotation]
+		if !ok {
+			continue
+		}
+		var sections ManSections
+		if err := json.Unmarshal([]byte(value), &sections); err != nil {
+			return nil, fmt.Errorf("invalid man sections of %q: %v", c.CommandPath(), err)
+		}
+		merged.Environment = mergeManEntries(merged.Environment, sections.Environment)
+		merged.ExitStatus = mergeManEntries(merged.ExitStatus, sections.ExitStatus)
+		merged.Files = mergeManEntries(merged.Files, sections.Files)
+		authors := map[string]bool{}
+		for _, author := range merged.Authors {
+			authors[author] = true
+		}
+		for _, author := range sections.Authors {
+			if !authors[author] {
+				merged.Authors = append(merged.Authors, author)
+			}
+		}
+		if sections.Bugs != "" {
+			merged.Bugs = sections.Bugs
+		}
+	}
+	return &merged, nil
+}
+
+// mergeManEntries returns the entries of base and additions, where the additions
+// replace the entries of base with the same name.
+func mergeManEntries(base, additions []ManEntry) []ManEntry {
+	merged := append([]ManEntry{}, base...)
+	for _, entry := range additions {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == entry.Name {
+				merged[i], replaced = entry, true
+			}
+		}
+		if !replaced {
+			merged = append(merged, entry)
+		}
+	}
+	return merged
 }
 
 // GenMan will generate a man page for the given command and write it to
@@ -110,8 +210,11 @@ func GenMan(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
 		return err
 	}
 
-	b := genMan(cmd, header)
-	_, err := w.Write(md2man.Render(b))
+	b, err := genMan(cmd, header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(md2man.Render(b))
 	return err
 }
 
@@ -140,20 +243,26 @@ func fillHeader(header *GenManHeader, name string, disableAutoGen bool) error {
 	return nil
 }
 
-func manPreamble(buf io.StringWriter, header *GenManHeader, cmd *cobra.Command, dashedName string) {
-	description := cmd.Long
+func manPreamble(buf io.StringWriter, header *GenManHeader, cmd *cobra.Command, d *CommandDoc, dashedName string) {
+	description := d.Description
 	if len(description) == 0 {
-		description = cmd.Short
+		description = d.Synopsis
 	}
 
 	cobra.WriteStringAndCheck(buf, fmt.Sprintf(`%% "%s" "%s" "%s" "%s" "%s"
 # NAME
 `, header.Title, header.Section, header.date, header.Source, header.Manual))
-	cobra.WriteStringAndCheck(buf, fmt.Sprintf("%s \\- %s\n\n", dashedName, cmd.Short))
+	cobra.WriteStringAndCheck(buf, fmt.Sprintf("%s \\- %s\n\n", dashedName, d.Synopsis))
 	cobra.WriteStringAndCheck(buf, "# SYNOPSIS\n")
 	cobra.WriteStringAndCheck(buf, fmt.Sprintf("**%s**\n\n", cmd.UseLine()))
 	cobra.WriteStringAndCheck(buf, "# DESCRIPTION\n")
 	cobra.WriteStringAndCheck(buf, description+"\n\n")
+	if !EnableFlagTables {
+		return
+	}
+	for _, p := range d.properties() {
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf("**%s:** %s\n\n", p.Label, p.Value))
+	}
 }
 
 func manPrintFlags(buf io.StringWriter, flags *pflag.FlagSet) {
@@ -161,11 +270,15 @@ func manPrintFlags(buf io.StringWriter, flags *pflag.FlagSet) {
 		if len(flag.Deprecated) > 0 || flag.Hidden {
 			return
 		}
+		name := flag.Name
+		if cobra.IsFlagNegatable(flag) {
+			name = "[no-]" + name
+		}
 		format := ""
 		if len(flag.Shorthand) > 0 && len(flag.ShorthandDeprecated) == 0 {
-			format = fmt.Sprintf("**-%s**, **--%s**", flag.Shorthand, flag.Name)
+			format = fmt.Sprintf("**-%s**, **--%s**", flag.Shorthand, name)
 		} else {
-			format = fmt.Sprintf("**--%s**", flag.Name)
+			format = fmt.Sprintf("**--%s**", name)
 		}
 		if len(flag.NoOptDefVal) > 0 {
 			format += "["
@@ -199,48 +312,109 @@ func manPrintOptions(buf io.StringWriter, command *cobra.Command) {
 	}
 }
 
-func genMan(cmd *cobra.Command, header *GenManHeader) []byte {
-	cmd.InitDefaultHelpCmd()
-	cmd.InitDefaultHelpFlag()
+func manPrintOptionDocs(buf io.StringWriter, opts []OptionDoc, inherited bool) {
+	for _, o := range visibleOptions(opts) {
+		name := o.Name
+		if o.Negatable {
+			name = "[no-]" + name
+		}
+		format := ""
+		if len(o.Shorthand) > 0 && len(o.ShorthandDeprecated) == 0 {
+			format = fmt.Sprintf("**-%s**, **--%s**", o.Shorthand, name)
+		} else {
+			format = fmt.Sprintf("**--%s**", name)
+		}
+		if len(o.NoOptDefaultValue) > 0 {
+			format += "["
+		}
+		if o.Type == "string" {
+			// put quotes on the value
+			format += "=%q"
+		} else {
+			format += "=%s"
+		}
+		if len(o.NoOptDefaultValue) > 0 {
+			format += "]"
+		}
+		format += "\n\t%s\n\n"
+		// There is no column for the type, so it leads the notes.
+		notes := append([]string{o.Type}, o.notes(inherited)...)
+		usage := strings.TrimLeft(o.Usage+" ("+strings.Join(notes, "; ")+")", " ")
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf(format, o.DefaultValue, usage))
+	}
+}
+
+func manPrintOptionsDoc(buf io.StringWriter, d *CommandDoc) {
+	if opts := visibleOptions(d.Options); len(opts) > 0 {
+		cobra.WriteStringAndCheck(buf, "# OPTIONS\n")
+		manPrintOptionDocs(buf, opts, false)
+		cobra.WriteStringAndCheck(buf, "\n")
+	}
+	if opts := visibleOptions(d.InheritedOptions); len(opts) > 0 {
+		cobra.WriteStringAndCheck(buf, "# OPTIONS INHERITED FROM PARENT COMMANDS\n")
+		manPrintOptionDocs(buf, opts, true)
+		cobra.WriteStringAndCheck(buf, "\n")
+	}
+}
+
+func manPrintEntries(buf io.StringWriter, title string, entries []ManEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	cobra.WriteStringAndCheck(buf, "# "+title+"\n")
+	for _, entry := range entries {
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf("**%s**\n\t%s\n\n", entry.Name, entry.Description))
+	}
+}
+
+func genMan(cmd *cobra.Command, header *GenManHeader) ([]byte, error) {
+	d := NewCommandDoc(cmd)
+
+	sections, err := manSections(cmd, header)
+	if err != nil {
+		return nil, err
+	}
 
 	// something like `rootcmd-subcmd1-subcmd2`
-	dashCommandName := strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+	dashCommandName := strings.ReplaceAll(d.Name, " ", "-")
 
 	buf := new(bytes.Buffer)
 
-	manPreamble(buf, header, cmd, dashCommandName)
-	manPrintOptions(buf, cmd)
-	if len(cmd.Example) > 0 {
+	manPreamble(buf, header, cmd, d, dashCommandName)
+	if EnableFlagTables {
+		manPrintOptionsDoc(buf, d)
+	} else {
+		manPrintOptions(buf, cmd)
+	}
+	manPrintEntries(buf, "EXIT STATUS", sections.ExitStatus)
+	manPrintEntries(buf, "ENVIRONMENT", sections.Environment)
+	manPrintEntries(buf, "FILES", sections.Files)
+	if sections.Bugs != "" {
+		buf.WriteString("# BUGS\n" + sections.Bugs + "\n\n")
+	}
+	if len(d.Example) > 0 {
 		buf.WriteString("# EXAMPLE\n")
-		buf.WriteString(fmt.Sprintf("```\n%s\n```\n", cmd.Example))
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n", d.Example))
+	}
+	if len(sections.Authors) > 0 {
+		buf.WriteString("# AUTHORS\n" + strings.Join(sections.Authors, ", ") + "\n\n")
 	}
-	if hasSeeAlso(cmd) {
+	if len(d.SeeAlso) > 0 {
 		buf.WriteString("# SEE ALSO\n")
-		seealsos := make([]string, 0)
-		if cmd.HasParent() {
-			parentPath := cmd.Parent().CommandPath()
-			dashParentPath := strings.ReplaceAll(parentPath, " ", "-")
-			seealso := fmt.Sprintf("**%s(%s)**", dashParentPath, header.Section)
-			seealsos = append(seealsos, seealso)
-			cmd.VisitParents(func(c *cobra.Command) {
-				if c.DisableAutoGenTag {
-					cmd.DisableAutoGenTag = c.DisableAutoGenTag
-				}
-			})
-		}
-		children := cmd.Commands()
-		sort.Sort(byName(children))
-		for _, c := range children {
-			if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
-				continue
-			}
-			seealso := fmt.Sprintf("**%s-%s(%s)**", dashCommandName, c.Name(), header.Section)
-			seealsos = append(seealsos, seealso)
+		seealsos := make([]string, 0, len(d.SeeAlso))
+		for _, s := range d.SeeAlso {
+			dashPath := strings.ReplaceAll(s.Name, " ", "-")
+			seealsos = append(seealsos, fmt.Sprintf("**%s(%s)**", dashPath, header.Section))
 		}
 		buf.WriteString(strings.Join(seealsos, ", ") + "\n")
+		cmd.VisitParents(func(c *cobra.Command) {
+			if c.DisableAutoGenTag {
+				cmd.DisableAutoGenTag = c.DisableAutoGenTag
+			}
+		})
 	}
 	if !cmd.DisableAutoGenTag {
 		buf.WriteString(fmt.Sprintf("# HISTORY\n%s Auto generated by spf13/cobra\n", header.Date.Format("2-Jan-2006")))
 	}
-	return buf.Bytes()
+	return buf.Bytes(), nil
 }
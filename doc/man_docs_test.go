@@ -150,6 +150,58 @@ func TestGenManSeeAlso(t *testing.T) {
 	}
 }
 
+func TestGenManSections(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "root", Run: emptyRun}
+	childCmd := &cobra.Command{Use: "child", Short: "the child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+	assertNoErr(t, SetManSections(rootCmd, &ManSections{
+		Environment: []ManEntry{{Name: "ROOT_HOME", Description: "the home directory"}},
+	}))
+	assertNoErr(t, SetManSections(childCmd, &ManSections{
+		ExitStatus: []ManEntry{{Name: "1", Description: "the child failed"}, {Name: "2", Description: "the child was interrupted"}},
+		Files:      []ManEntry{{Name: "/etc/child.conf", Description: "the configuration"}},
+		Authors:    []string{"Jane Doe"},
+	}))
+
+	header := &GenManHeader{Title: "Project", ManSections: ManSections{
+		ExitStatus: []ManEntry{{Name: "0", Description: "success"}, {Name: "1", Description: "failure"}},
+		Authors:    []string{"Jane Doe", "John Doe"},
+		Bugs:       "Report bugs at https://example.com/issues.",
+	}}
+	buf := new(bytes.Buffer)
+	if err := GenMan(childCmd, header, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, ".SH EXIT STATUS\n.PP\n\\fB0\\fP\n\tsuccess\n\n.PP\n\\fB1\\fP\n\tthe child failed\n\n.PP\n\\fB2\\fP")
+	checkStringContains(t, output, ".SH ENVIRONMENT\n.PP\n\\fBROOT_HOME\\fP\n\tthe home directory")
+	checkStringContains(t, output, ".SH FILES\n.PP\n\\fB/etc/child.conf\\fP\n\tthe configuration")
+	checkStringContains(t, output, ".SH BUGS\n.PP\nReport bugs at")
+	checkStringContains(t, output, ".SH AUTHORS\n.PP\nJane Doe, John Doe\n")
+	checkStringOmits(t, output, "failure")
+
+	for _, section := range []string{"EXIT STATUS", "ENVIRONMENT", "FILES", "BUGS", "AUTHORS", "SEE ALSO"} {
+		if !strings.Contains(output, ".SH "+section) {
+			t.Errorf("Expected the %s section", section)
+		}
+	}
+	if strings.Index(output, ".SH EXIT STATUS") > strings.Index(output, ".SH ENVIRONMENT") ||
+		strings.Index(output, ".SH BUGS") > strings.Index(output, ".SH AUTHORS") {
+		t.Errorf("Expected the sections in the standard order")
+	}
+	if len(header.ExitStatus) != 2 || header.ExitStatus[1].Description != "failure" || len(header.Authors) != 2 {
+		t.Errorf("Expected the header to be left unchanged")
+	}
+}
+
+func TestGenManInvalidSections(t *testing.T) {
+	c := &cobra.Command{Use: "c", Run: emptyRun, Annotations: map[string]string{ManSectionsAnnotation: "{"}}
+	if err := GenMan(c, nil, new(bytes.Buffer)); err == nil {
+		t.Errorf("Expected an error for invalid man sections")
+	}
+}
+
 func TestManPrintFlagsHidesShortDeprecated(t *testing.T) {
 	c := &cobra.Command{}
 	c.Flags().StringP("foo", "f", "default", "Foo flag")
@@ -20,7 +20,6 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -31,23 +30,54 @@ const markdownExtension = ".md"
 
 func printOptions(buf *bytes.Buffer, cmd *cobra.Command, name string) error {
 	flags := cmd.NonInheritedFlags()
-	flags.SetOutput(buf)
 	if flags.HasAvailableFlags() {
 		buf.WriteString("### Options\n\n```\n")
-		flags.PrintDefaults()
+		buf.WriteString(cobra.FormatFlagUsages(flags, 0))
 		buf.WriteString("```\n\n")
 	}
 
 	parentFlags := cmd.InheritedFlags()
-	parentFlags.SetOutput(buf)
 	if parentFlags.HasAvailableFlags() {
 		buf.WriteString("### Options inherited from parent commands\n\n```\n")
-		parentFlags.PrintDefaults()
+		buf.WriteString(cobra.FormatFlagUsages(parentFlags, 0))
 		buf.WriteString("```\n\n")
 	}
 	return nil
 }
 
+func printOptionsTables(buf *bytes.Buffer, d *CommandDoc) {
+	if opts := visibleOptions(d.Options); len(opts) > 0 {
+		buf.WriteString("### Options\n\n")
+		printOptionsTable(buf, opts, false)
+	}
+
+	if opts := visibleOptions(d.InheritedOptions); len(opts) > 0 {
+		buf.WriteString("### Options inherited from parent commands\n\n")
+		printOptionsTable(buf, opts, true)
+	}
+}
+
+func printOptionsTable(buf *bytes.Buffer, opts []OptionDoc, inherited bool) {
+	buf.WriteString("| Flag | Type | Default | Description |\n")
+	buf.WriteString("|------|------|---------|-------------|\n")
+	for _, o := range opts {
+		def := o.displayDefault()
+		if def != "" {
+			def = "`" + escapeMarkdownCell(def) + "`"
+		}
+		buf.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+			o.displayFlag(), o.Type, def, escapeMarkdownCell(o.description(inherited))))
+	}
+	buf.WriteString("\n")
+}
+
+// escapeMarkdownCell escapes the column separator and line breaks so that
+// text fits in a single table cell.
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
 // GenMarkdown creates markdown output.
 func GenMarkdown(cmd *cobra.Command, w io.Writer) error {
 	return GenMarkdownCustom(cmd, w, func(s string) string { return s })
@@ -55,59 +85,49 @@ func GenMarkdown(cmd *cobra.Command, w io.Writer) error {
 
 // GenMarkdownCustom creates custom markdown output.
 func GenMarkdownCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string) string) error {
-	cmd.InitDefaultHelpCmd()
-	cmd.InitDefaultHelpFlag()
-
+	d := NewCommandDoc(cmd)
 	buf := new(bytes.Buffer)
-	name := cmd.CommandPath()
 
-	buf.WriteString("## " + name + "\n\n")
-	buf.WriteString(cmd.Short + "\n\n")
-	if len(cmd.Long) > 0 {
+	buf.WriteString("## " + d.Name + "\n\n")
+	buf.WriteString(d.Synopsis + "\n\n")
+	if len(d.Description) > 0 {
 		buf.WriteString("### Synopsis\n\n")
-		buf.WriteString(cmd.Long + "\n\n")
+		buf.WriteString(d.Description + "\n\n")
+	}
+
+	if len(d.Usage) > 0 {
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", d.Usage))
 	}
 
-	if cmd.Runnable() {
-		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", cmd.UseLine()))
+	if props := d.properties(); EnableFlagTables && len(props) > 0 {
+		for _, p := range props {
+			buf.WriteString(fmt.Sprintf("* **%s:** %s\n", p.Label, p.Value))
+		}
+		buf.WriteString("\n")
 	}
 
-	if len(cmd.Example) > 0 {
+	if len(d.Example) > 0 {
 		buf.WriteString("### Examples\n\n")
-		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", cmd.Example))
+		buf.WriteString(fmt.Sprintf("```\n%s\n```\n\n", d.Example))
 	}
 
-	if err := printOptions(buf, cmd, name); err != nil {
+	if EnableFlagTables {
+		printOptionsTables(buf, d)
+	} else if err := printOptions(buf, cmd, d.Name); err != nil {
 		return err
 	}
-	if hasSeeAlso(cmd) {
+	if len(d.SeeAlso) > 0 {
 		buf.WriteString("### SEE ALSO\n\n")
-		if cmd.HasParent() {
-			parent := cmd.Parent()
-			pname := parent.CommandPath()
-			link := pname + markdownExtension
-			link = strings.ReplaceAll(link, " ", "_")
-			buf.WriteString(fmt.Sprintf("* [%s](%s)\t - %s\n", pname, linkHandler(link), parent.Short))
-			cmd.VisitParents(func(c *cobra.Command) {
-				if c.DisableAutoGenTag {
-					cmd.DisableAutoGenTag = c.DisableAutoGenTag
-				}
-			})
-		}
-
-		children := cmd.Commands()
-		sort.Sort(byName(children))
-
-		for _, child := range children {
-			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
-				continue
-			}
-			cname := name + " " + child.Name()
-			link := cname + markdownExtension
-			link = strings.ReplaceAll(link, " ", "_")
-			buf.WriteString(fmt.Sprintf("* [%s](%s)\t - %s\n", cname, linkHandler(link), child.Short))
+		for _, s := range d.SeeAlso {
+			link := linkHandler(s.Ref + markdownExtension)
+			buf.WriteString(fmt.Sprintf("* [%s](%s)\t - %s\n", s.Name, link, s.Short))
 		}
 		buf.WriteString("\n")
+		cmd.VisitParents(func(c *cobra.Command) {
+			if c.DisableAutoGenTag {
+				cmd.DisableAutoGenTag = c.DisableAutoGenTag
+			}
+		})
 	}
 	if !cmd.DisableAutoGenTag {
 		buf.WriteString("###### Auto generated by spf13/cobra on " + time.Now().Format("2-Jan-2006") + "\n")
@@ -42,6 +42,23 @@ func TestGenMdDoc(t *testing.T) {
 	checkStringContains(t, output, "Options inherited from parent commands")
 }
 
+func TestGenMdDocNegatableFlag(t *testing.T) {
+	c := &cobra.Command{Use: "c", Run: emptyRun}
+	c.Flags().Bool("cache", true, "use the cache")
+	if err := c.MarkFlagNegatable("cache"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "--[no-]cache")
+	checkStringOmits(t, output, "--cache")
+}
+
 func TestGenMdDocWithNoLongOrSynopsis(t *testing.T) {
 	// We generate on subcommand so we have both subcommands and parents.
 	buf := new(bytes.Buffer)
@@ -0,0 +1,301 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// YAMLSchemaVersion is the version of the schema of the documents written by
+// GenYaml. It is incremented whenever a field is removed or changes meaning;
+// new fields may be added without changing it. Documents without a
+// schema_version field, written before the schema was versioned, conform to
+// version 1.
+const YAMLSchemaVersion = 1
+
+// EnableFlagTables makes the Markdown and ReST generators list the flags in a
+// table of their type, default value and notes, such as whether they are required,
+// and the man generator add the type and notes to their usage. These generators
+// then also list the metadata of a command, such as its aliases, after its synopsis.
+// By default they write the flags as the help output does, in the same format as
+// before the document model.
+var EnableFlagTables = false
+
+// CommandDoc is the document model shared by the generators of this package:
+// every generator renders the same CommandDoc, built by NewCommandDoc, in its
+// own format. GenYaml writes it as is.
+type CommandDoc struct {
+	// SchemaVersion is the YAMLSchemaVersion the document conforms to.
+	SchemaVersion int `yaml:"schema_version"`
+	// Name is the full path of the command, such as "root sub".
+	Name        string `yaml:"name"`
+	Synopsis    string `yaml:"synopsis,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	// Usage is the usage line of the command, or empty if it is not runnable.
+	Usage      string    `yaml:"usage,omitempty"`
+	Aliases    []string  `yaml:"aliases,omitempty"`
+	Deprecated string    `yaml:"deprecated,omitempty"`
+	Group      *GroupDoc `yaml:"group,omitempty"`
+	// ValidArgs are the arguments proposed by shell completion, without their descriptions.
+	ValidArgs  []string `yaml:"valid_args,omitempty"`
+	ArgAliases []string `yaml:"arg_aliases,omitempty"`
+	// Annotations are the annotations of the command, except those set by Cobra.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// Options are the flags declared on the command, including its persistent flags.
+	Options []OptionDoc `yaml:"options,omitempty"`
+	// InheritedOptions are the persistent flags the command inherits from its parents.
+	InheritedOptions []OptionDoc `yaml:"inherited_options,omitempty"`
+	Example          string      `yaml:"example,omitempty"`
+	// SeeAlso lists the parent of the command followed by its available
+	// subcommands, sorted by name.
+	SeeAlso []SeeAlsoDoc `yaml:"see_also,omitempty"`
+}
+
+// GroupDoc is the group a command is listed under in the help of its parent.
+type GroupDoc struct {
+	ID    string `yaml:"id"`
+	Title string `yaml:"title"`
+}
+
+// OptionDoc describes a flag. Hidden and deprecated flags are part of the
+// model; the generators other than GenYaml leave them out, and GenYaml
+// leaves out the shorthand of a flag when the shorthand is deprecated.
+type OptionDoc struct {
+	Name              string `yaml:"name"`
+	Shorthand         string `yaml:"shorthand,omitempty"`
+	Type              string `yaml:"type"`
+	DefaultValue      string `yaml:"default_value,omitempty"`
+	NoOptDefaultValue string `yaml:"no_opt_default_value,omitempty"`
+	Usage             string `yaml:"usage,omitempty"`
+	// Persistent is true when the flag is inherited by subcommands.
+	Persistent          bool   `yaml:"persistent,omitempty"`
+	Required            bool   `yaml:"required,omitempty"`
+	Negatable           bool   `yaml:"negatable,omitempty"`
+	Hidden              bool   `yaml:"hidden,omitempty"`
+	Deprecated          string `yaml:"deprecated,omitempty"`
+	ShorthandDeprecated string `yaml:"shorthand_deprecated,omitempty"`
+}
+
+// SeeAlsoDoc is a command related to the documented one.
+type SeeAlsoDoc struct {
+	// Name is the full path of the command, such as "root sub".
+	Name  string
+	Short string
+	// Ref is the name with spaces replaced by underscores, which the
+	// generators use as file name and anchor.
+	Ref string
+}
+
+// MarshalYAML writes s as "name - short", as in documents without a schema version.
+func (s SeeAlsoDoc) MarshalYAML() (interface{}, error) {
+	return s.Name + " - " + s.Short, nil
+}
+
+// NewCommandDoc returns the document model of cmd.
+func NewCommandDoc(cmd *cobra.Command) *CommandDoc {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+
+	d := &CommandDoc{
+		SchemaVersion: YAMLSchemaVersion,
+		Name:          cmd.CommandPath(),
+		Synopsis:      cmd.Short,
+		Description:   cmd.Long,
+		Aliases:       cmd.Aliases,
+		Deprecated:    cmd.Deprecated,
+		ArgAliases:    cmd.ArgAliases,
+		Example:       cmd.Example,
+	}
+	if cmd.Runnable() {
+		d.Usage = cmd.UseLine()
+	}
+	if cmd.GroupID != "" && cmd.HasParent() {
+		for _, g := range cmd.Parent().Groups() {
+			if g.ID == cmd.GroupID {
+				d.Group = &GroupDoc{ID: g.ID, Title: g.Title}
+			}
+		}
+	}
+	for _, arg := range cmd.ValidArgs {
+		// Valid arguments may carry a completion description after a tab.
+		d.ValidArgs = append(d.ValidArgs, strings.SplitN(arg, "\t", 2)[0])
+	}
+	for key, value := range cmd.Annotations {
+		if strings.HasPrefix(key, "cobra_annotation_") {
+			continue
+		}
+		if d.Annotations == nil {
+			d.Annotations = map[string]string{}
+		}
+		d.Annotations[key] = value
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+		d.Options = append(d.Options, newOptionDoc(f, persistent.Lookup(f.Name) == f))
+	})
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		d.InheritedOptions = append(d.InheritedOptions, newOptionDoc(f, true))
+	})
+
+	if cmd.HasParent() {
+		d.SeeAlso = append(d.SeeAlso, newSeeAlsoDoc(cmd.Parent()))
+	}
+	children := cmd.Commands()
+	sort.Sort(byName(children))
+	for _, child := range children {
+		if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		d.SeeAlso = append(d.SeeAlso, newSeeAlsoDoc(child))
+	}
+	return d
+}
+
+func newOptionDoc(f *pflag.Flag, persistent bool) OptionDoc {
+	required := false
+	if v, ok := f.Annotations[cobra.BashCompOneRequiredFlag]; ok && len(v) > 0 && v[0] == "true" {
+		required = true
+	}
+	return OptionDoc{
+		Name:                f.Name,
+		Shorthand:           f.Shorthand,
+		Type:                f.Value.Type(),
+		DefaultValue:        f.DefValue,
+		NoOptDefaultValue:   f.NoOptDefVal,
+		Usage:               f.Usage,
+		Persistent:          persistent,
+		Required:            required,
+		Negatable:           cobra.IsFlagNegatable(f),
+		Hidden:              f.Hidden,
+		Deprecated:          f.Deprecated,
+		ShorthandDeprecated: f.ShorthandDeprecated,
+	}
+}
+
+func newSeeAlsoDoc(cmd *cobra.Command) SeeAlsoDoc {
+	name := cmd.CommandPath()
+	return SeeAlsoDoc{
+		Name:  name,
+		Short: cmd.Short,
+		Ref:   strings.ReplaceAll(name, " ", "_"),
+	}
+}
+
+// docProperty is a labelled piece of metadata, such as the aliases of a command,
+// which the generators list after the synopsis.
+type docProperty struct {
+	Label string
+	Value string
+}
+
+// properties returns the metadata of d that has no section of its own,
+// in the order the generators list it.
+func (d *CommandDoc) properties() []docProperty {
+	var props []docProperty
+	if d.Deprecated != "" {
+		props = append(props, docProperty{"Deprecated", d.Deprecated})
+	}
+	if len(d.Aliases) > 0 {
+		props = append(props, docProperty{"Aliases", strings.Join(d.Aliases, ", ")})
+	}
+	if d.Group != nil {
+		props = append(props, docProperty{"Group", d.Group.Title})
+	}
+	if len(d.ValidArgs) > 0 {
+		props = append(props, docProperty{"Valid arguments", strings.Join(d.ValidArgs, ", ")})
+	}
+	if len(d.ArgAliases) > 0 {
+		props = append(props, docProperty{"Argument aliases", strings.Join(d.ArgAliases, ", ")})
+	}
+	keys := make([]string, 0, len(d.Annotations))
+	for key := range d.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		props = append(props, docProperty{key, d.Annotations[key]})
+	}
+	return props
+}
+
+// visibleOptions returns the options that are neither hidden nor deprecated,
+// which are the ones the help output shows.
+func visibleOptions(opts []OptionDoc) []OptionDoc {
+	var visible []OptionDoc
+	for _, o := range opts {
+		if o.Hidden || o.Deprecated != "" {
+			continue
+		}
+		visible = append(visible, o)
+	}
+	return visible
+}
+
+// displayFlag returns the flag as it is typed, such as "-v, --verbose", leaving
+// out a deprecated shorthand and adding a [no-] prefix to negatable flags.
+func (o OptionDoc) displayFlag() string {
+	name := "--" + o.Name
+	if o.Negatable {
+		name = "--[no-]" + o.Name
+	}
+	if o.Shorthand != "" && o.ShorthandDeprecated == "" {
+		name = "-" + o.Shorthand + ", " + name
+	}
+	return name
+}
+
+// displayDefault returns the default value of o, or an empty string for the
+// zero values the help output does not show either.
+func (o OptionDoc) displayDefault() string {
+	switch o.DefaultValue {
+	case "false", "0", "[]", "map[]":
+		return ""
+	}
+	return o.DefaultValue
+}
+
+// notes returns the facts about o that are not shown in its name, type,
+// default value or usage, such as whether it is persistent. Inherited options
+// are all persistent, so that is only noted for the options of the command.
+func (o OptionDoc) notes(inherited bool) []string {
+	var notes []string
+	if o.Persistent && !inherited {
+		notes = append(notes, "persistent")
+	}
+	if o.Required {
+		notes = append(notes, "required")
+	}
+	if o.ShorthandDeprecated != "" {
+		notes = append(notes, "shorthand -"+o.Shorthand+" deprecated: "+o.ShorthandDeprecated)
+	}
+	return notes
+}
+
+// description returns the usage of o followed by its notes in parentheses.
+func (o OptionDoc) description(inherited bool) string {
+	notes := o.notes(inherited)
+	if len(notes) == 0 {
+		return o.Usage
+	}
+	if o.Usage == "" {
+		return "(" + strings.Join(notes, "; ") + ")"
+	}
+	return o.Usage + " (" + strings.Join(notes, "; ") + ")"
+}
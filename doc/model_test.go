@@ -0,0 +1,161 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doc
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newModelTestCommand(t *testing.T) *cobra.Command {
+	root := &cobra.Command{Use: "app", Short: "The app"}
+	root.AddGroup(&cobra.Group{ID: "manage", Title: "Management Commands:"})
+	root.PersistentFlags().String("config", "", "config file")
+
+	c := &cobra.Command{
+		Use:         "deploy [env]",
+		Short:       "Deploy the app",
+		Aliases:     []string{"ship", "push"},
+		Deprecated:  "use release instead",
+		GroupID:     "manage",
+		ValidArgs:   []string{"prod\tthe production environment", "staging"},
+		ArgAliases:  []string{"production"},
+		Annotations: map[string]string{"since": "1.2"},
+		Run:         emptyRun,
+	}
+	c.PersistentFlags().StringP("region", "r", "eu", "region to deploy to")
+	c.Flags().StringP("tag", "t", "", "image tag")
+	c.Flags().Bool("wait", false, "wait for the rollout")
+	assertNoErr(t, c.MarkFlagRequired("tag"))
+	assertNoErr(t, c.PersistentFlags().MarkShorthandDeprecated("region", "use --region"))
+	assertNoErr(t, SetManSections(c, &ManSections{Bugs: "Report bugs upstream."}))
+	assertNoErr(t, c.MarkFlagNegatable("wait"))
+	root.AddCommand(c)
+	return c
+}
+
+func TestNewCommandDoc(t *testing.T) {
+	d := NewCommandDoc(newModelTestCommand(t))
+
+	if d.SchemaVersion != YAMLSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", YAMLSchemaVersion, d.SchemaVersion)
+	}
+	if d.Name != "app deploy" || d.Usage != "app deploy [env] [flags]" {
+		t.Errorf("Unexpected name %q or usage %q", d.Name, d.Usage)
+	}
+	if d.Deprecated != "use release instead" {
+		t.Errorf("Unexpected deprecation %q", d.Deprecated)
+	}
+	if !reflect.DeepEqual(d.Group, &GroupDoc{ID: "manage", Title: "Management Commands:"}) {
+		t.Errorf("Unexpected group %+v", d.Group)
+	}
+	if !reflect.DeepEqual(d.ValidArgs, []string{"prod", "staging"}) {
+		t.Errorf("Expected valid args without descriptions, got %v", d.ValidArgs)
+	}
+	if !reflect.DeepEqual(d.Annotations, map[string]string{"since": "1.2"}) {
+		t.Errorf("Expected the annotations set by Cobra to be left out, got %v", d.Annotations)
+	}
+
+	options := map[string]OptionDoc{}
+	for _, o := range d.Options {
+		options[o.Name] = o
+	}
+	if o := options["region"]; !o.Persistent || o.ShorthandDeprecated != "use --region" || o.Type != "string" {
+		t.Errorf("Unexpected region option %+v", o)
+	}
+	if o := options["tag"]; o.Persistent || !o.Required {
+		t.Errorf("Unexpected tag option %+v", o)
+	}
+	if o := options["wait"]; !o.Negatable || o.Type != "bool" {
+		t.Errorf("Unexpected wait option %+v", o)
+	}
+	if len(d.InheritedOptions) != 1 || d.InheritedOptions[0].Name != "config" || !d.InheritedOptions[0].Persistent {
+		t.Errorf("Unexpected inherited options %+v", d.InheritedOptions)
+	}
+	if len(d.SeeAlso) != 1 || d.SeeAlso[0] != (SeeAlsoDoc{Name: "app", Short: "The app", Ref: "app"}) {
+		t.Errorf("Unexpected see also %+v", d.SeeAlso)
+	}
+}
+
+func TestGeneratorsRenderMetadata(t *testing.T) {
+	defer func() { EnableFlagTables = false }()
+	EnableFlagTables = true
+
+	generators := map[string]func(*cobra.Command, io.Writer) error{
+		"markdown": GenMarkdown,
+		"rest":     GenReST,
+		"asciidoc": GenAsciidoc,
+		"man": func(c *cobra.Command, w io.Writer) error {
+			return GenMan(c, nil, w)
+		},
+	}
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := gen(newModelTestCommand(t), buf); err != nil {
+				t.Fatal(err)
+			}
+			output := buf.String()
+
+			checkStringContains(t, output, "Deprecated")
+			checkStringContains(t, output, "use release instead")
+			checkStringContains(t, output, "ship, push")
+			checkStringContains(t, output, "Management Commands:")
+			checkStringContains(t, output, "prod, staging")
+			checkStringContains(t, output, "production")
+			checkStringContains(t, output, "since")
+			checkStringContains(t, output, "persistent; shorthand -r deprecated: use --region)")
+			checkStringContains(t, output, "image tag (")
+			checkStringContains(t, output, "required")
+			checkStringContains(t, output, "[no-]wait")
+			checkStringOmits(t, output, "the production environment")
+			checkStringOmits(t, output, ManSectionsAnnotation)
+		})
+	}
+}
+
+func TestGeneratorsKeepFormatWithoutFlagTables(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(newModelTestCommand(t), buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	checkStringContains(t, output, "### Options\n\n```\n")
+	checkStringContains(t, output, "      --[no-]wait       wait for the rollout\n")
+	checkStringOmits(t, output, "| Flag |")
+	checkStringOmits(t, output, "Aliases")
+
+	buf.Reset()
+	if err := GenReST(newModelTestCommand(t), buf); err != nil {
+		t.Fatal(err)
+	}
+	output = buf.String()
+	checkStringContains(t, output, "Options\n~~~~~~~\n\n::\n\n")
+	checkStringOmits(t, output, "list-table")
+	checkStringOmits(t, output, ":Aliases:")
+
+	buf.Reset()
+	if err := GenMan(newModelTestCommand(t), nil, buf); err != nil {
+		t.Fatal(err)
+	}
+	output = buf.String()
+	checkStringContains(t, output, "image tag\n")
+	checkStringOmits(t, output, "(string")
+	checkStringOmits(t, output, "Aliases")
+}
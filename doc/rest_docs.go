@@ -20,7 +20,6 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -29,25 +28,64 @@ import (
 
 func printOptionsReST(buf *bytes.Buffer, cmd *cobra.Command, name string) error {
 	flags := cmd.NonInheritedFlags()
-	flags.SetOutput(buf)
 	if flags.HasAvailableFlags() {
 		buf.WriteString("Options\n")
 		buf.WriteString("~~~~~~~\n\n::\n\n")
-		flags.PrintDefaults()
+		buf.WriteString(cobra.FormatFlagUsages(flags, 0))
 		buf.WriteString("\n")
 	}
 
 	parentFlags := cmd.InheritedFlags()
-	parentFlags.SetOutput(buf)
 	if parentFlags.HasAvailableFlags() {
 		buf.WriteString("Options inherited from parent commands\n")
 		buf.WriteString("~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~\n\n::\n\n")
-		parentFlags.PrintDefaults()
+		buf.WriteString(cobra.FormatFlagUsages(parentFlags, 0))
 		buf.WriteString("\n")
 	}
 	return nil
 }
 
+func printOptionsTablesReST(buf *bytes.Buffer, d *CommandDoc) {
+	if opts := visibleOptions(d.Options); len(opts) > 0 {
+		buf.WriteString("Options\n")
+		buf.WriteString("~~~~~~~\n\n")
+		printOptionsTableReST(buf, opts, false)
+	}
+
+	if opts := visibleOptions(d.InheritedOptions); len(opts) > 0 {
+		buf.WriteString("Options inherited from parent commands\n")
+		buf.WriteString("~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~\n\n")
+		printOptionsTableReST(buf, opts, true)
+	}
+}
+
+func printOptionsTableReST(buf *bytes.Buffer, opts []OptionDoc, inherited bool) {
+	buf.WriteString(".. list-table::\n")
+	buf.WriteString("   :header-rows: 1\n\n")
+	buf.WriteString("   * - Flag\n     - Type\n     - Default\n     - Description\n")
+	for _, o := range opts {
+		def := o.displayDefault()
+		if def != "" {
+			def = "``" + def + "``"
+		}
+		buf.WriteString(fmt.Sprintf("   * - ``%s``\n", o.displayFlag()))
+		writeListTableCell(buf, o.Type)
+		writeListTableCell(buf, def)
+		writeListTableCell(buf, o.description(inherited))
+	}
+	buf.WriteString("\n")
+}
+
+// writeListTableCell writes a cell of a list-table row, indenting the lines
+// that follow the first one.
+func writeListTableCell(buf *bytes.Buffer, s string) {
+	if s == "" {
+		buf.WriteString("     -\n")
+		return
+	}
+	buf.WriteString("     - " + strings.TrimPrefix(indentString(s, "       "), "       ") + "\n")
+}
+
 // defaultLinkHandler for default ReST hyperlink markup
 func defaultLinkHandler(name, ref string) string {
 	return fmt.Sprintf("`%s <%s.rst>`_", name, ref)
@@ -60,67 +98,58 @@ func GenReST(cmd *cobra.Command, w io.Writer) error {
 
 // GenReSTCustom creates custom reStructured Text output.
 func GenReSTCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string) error {
-	cmd.InitDefaultHelpCmd()
-	cmd.InitDefaultHelpFlag()
-
+	d := NewCommandDoc(cmd)
 	buf := new(bytes.Buffer)
-	name := cmd.CommandPath()
 
-	short := cmd.Short
-	long := cmd.Long
+	short := d.Synopsis
+	long := d.Description
 	if len(long) == 0 {
 		long = short
 	}
-	ref := strings.ReplaceAll(name, " ", "_")
+	ref := strings.ReplaceAll(d.Name, " ", "_")
 
 	buf.WriteString(".. _" + ref + ":\n\n")
-	buf.WriteString(name + "\n")
-	buf.WriteString(strings.Repeat("-", len(name)) + "\n\n")
+	buf.WriteString(d.Name + "\n")
+	buf.WriteString(strings.Repeat("-", len(d.Name)) + "\n\n")
 	buf.WriteString(short + "\n\n")
 	buf.WriteString("Synopsis\n")
 	buf.WriteString("~~~~~~~~\n\n")
 	buf.WriteString("\n" + long + "\n\n")
 
-	if cmd.Runnable() {
-		buf.WriteString(fmt.Sprintf("::\n\n  %s\n\n", cmd.UseLine()))
+	if len(d.Usage) > 0 {
+		buf.WriteString(fmt.Sprintf("::\n\n  %s\n\n", d.Usage))
+	}
+
+	if props := d.properties(); EnableFlagTables && len(props) > 0 {
+		for _, p := range props {
+			buf.WriteString(fmt.Sprintf(":%s: %s\n", p.Label, p.Value))
+		}
+		buf.WriteString("\n")
 	}
 
-	if len(cmd.Example) > 0 {
+	if len(d.Example) > 0 {
 		buf.WriteString("Examples\n")
 		buf.WriteString("~~~~~~~~\n\n")
-		buf.WriteString(fmt.Sprintf("::\n\n%s\n\n", indentString(cmd.Example, "  ")))
+		buf.WriteString(fmt.Sprintf("::\n\n%s\n\n", indentString(d.Example, "  ")))
 	}
 
-	if err := printOptionsReST(buf, cmd, name); err != nil {
+	if EnableFlagTables {
+		printOptionsTablesReST(buf, d)
+	} else if err := printOptionsReST(buf, cmd, d.Name); err != nil {
 		return err
 	}
-	if hasSeeAlso(cmd) {
+	if len(d.SeeAlso) > 0 {
 		buf.WriteString("SEE ALSO\n")
 		buf.WriteString("~~~~~~~~\n\n")
-		if cmd.HasParent() {
-			parent := cmd.Parent()
-			pname := parent.CommandPath()
-			ref = strings.ReplaceAll(pname, " ", "_")
-			buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(pname, ref), parent.Short))
-			cmd.VisitParents(func(c *cobra.Command) {
-				if c.DisableAutoGenTag {
-					cmd.DisableAutoGenTag = c.DisableAutoGenTag
-				}
-			})
-		}
-
-		children := cmd.Commands()
-		sort.Sort(byName(children))
-
-		for _, child := range children {
-			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
-				continue
-			}
-			cname := name + " " + child.Name()
-			ref = strings.ReplaceAll(cname, " ", "_")
-			buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(cname, ref), child.Short))
+		for _, s := range d.SeeAlso {
+			buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(s.Name, s.Ref), s.Short))
 		}
 		buf.WriteString("\n")
+		cmd.VisitParents(func(c *cobra.Command) {
+			if c.DisableAutoGenTag {
+				cmd.DisableAutoGenTag = c.DisableAutoGenTag
+			}
+		})
 	}
 	if !cmd.DisableAutoGenTag {
 		buf.WriteString("*Auto generated by spf13/cobra on " + time.Now().Format("2-Jan-2006") + "*\n")
@@ -19,32 +19,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
 )
 
-type cmdOption struct {
-	Name         string
-	Shorthand    string `yaml:",omitempty"`
-	DefaultValue string `yaml:"default_value,omitempty"`
-	Usage        string `yaml:",omitempty"`
-}
-
-type cmdDoc struct {
-	Name             string
-	Synopsis         string      `yaml:",omitempty"`
-	Description      string      `yaml:",omitempty"`
-	Usage            string      `yaml:",omitempty"`
-	Options          []cmdOption `yaml:",omitempty"`
-	InheritedOptions []cmdOption `yaml:"inherited_options,omitempty"`
-	Example          string      `yaml:",omitempty"`
-	SeeAlso          []string    `yaml:"see_also,omitempty"`
-}
-
 // GenYamlTree creates yaml structured ref files for this command and all descendants
 // in the directory given. This function may not work
 // correctly if your command names have `-` in them. If you have `cmd` with two
@@ -90,51 +70,17 @@ func GenYaml(cmd *cobra.Command, w io.Writer) error {
 }
 
 // GenYamlCustom creates custom yaml output.
+// The document is a CommandDoc conforming to YAMLSchemaVersion.
 func GenYamlCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string) string) error {
-	cmd.InitDefaultHelpCmd()
-	cmd.InitDefaultHelpFlag()
-
-	yamlDoc := cmdDoc{}
-	yamlDoc.Name = cmd.CommandPath()
-
-	yamlDoc.Synopsis = forceMultiLine(cmd.Short)
-	yamlDoc.Description = forceMultiLine(cmd.Long)
-
-	if cmd.Runnable() {
-		yamlDoc.Usage = cmd.UseLine()
-	}
-
-	if len(cmd.Example) > 0 {
-		yamlDoc.Example = cmd.Example
-	}
+	yamlDoc := NewCommandDoc(cmd)
 
-	flags := cmd.NonInheritedFlags()
-	if flags.HasFlags() {
-		yamlDoc.Options = genFlagResult(flags)
-	}
-	flags = cmd.InheritedFlags()
-	if flags.HasFlags() {
-		yamlDoc.InheritedOptions = genFlagResult(flags)
-	}
-
-	if hasSeeAlso(cmd) {
-		result := []string{}
-		if cmd.HasParent() {
-			parent := cmd.Parent()
-			result = append(result, parent.CommandPath()+" - "+parent.Short)
-		}
-		children := cmd.Commands()
-		sort.Sort(byName(children))
-		for _, child := range children {
-			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
-				continue
-			}
-			result = append(result, child.CommandPath()+" - "+child.Short)
-		}
-		yamlDoc.SeeAlso = result
-	}
+	// Long single-line strings are written as multi-line strings.
+	yamlDoc.Synopsis = forceMultiLine(yamlDoc.Synopsis)
+	yamlDoc.Description = forceMultiLine(yamlDoc.Description)
+	yamlOptions(yamlDoc.Options)
+	yamlOptions(yamlDoc.InheritedOptions)
 
-	final, err := yaml.Marshal(&yamlDoc)
+	final, err := yaml.Marshal(yamlDoc)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -146,30 +92,18 @@ func GenYamlCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string) str
 	return nil
 }
 
-func genFlagResult(flags *pflag.FlagSet) []cmdOption {
-	var result []cmdOption
-
-	flags.VisitAll(func(flag *pflag.Flag) {
-		// Todo, when we mark a shorthand is deprecated, but specify an empty message.
-		// The flag.ShorthandDeprecated is empty as the shorthand is deprecated.
-		// Using len(flag.ShorthandDeprecated) > 0 can't handle this, others are ok.
-		if !(len(flag.ShorthandDeprecated) > 0) && len(flag.Shorthand) > 0 {
-			opt := cmdOption{
-				flag.Name,
-				flag.Shorthand,
-				flag.DefValue,
-				forceMultiLine(flag.Usage),
-			}
-			result = append(result, opt)
-		} else {
-			opt := cmdOption{
-				Name:         flag.Name,
-				DefaultValue: forceMultiLine(flag.DefValue),
-				Usage:        forceMultiLine(flag.Usage),
-			}
-			result = append(result, opt)
+// yamlOptions adapts opts to the documents written before the schema was
+// versioned: all the flags are listed, hidden and deprecated ones included,
+// but a deprecated shorthand is left out.
+func yamlOptions(opts []OptionDoc) {
+	for i := range opts {
+		o := &opts[i]
+		if len(o.ShorthandDeprecated) > 0 {
+			o.Shorthand = ""
 		}
-	})
-
-	return result
+		if len(o.Shorthand) == 0 {
+			o.DefaultValue = forceMultiLine(o.DefaultValue)
+		}
+		o.Usage = forceMultiLine(o.Usage)
+	}
 }
@@ -99,3 +99,42 @@ func BenchmarkGenYamlToFile(b *testing.B) {
 		}
 	}
 }
+
+func TestGenYamlSchema(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenYaml(newModelTestCommand(t), buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, fmt.Sprintf("schema_version: %d\n", YAMLSchemaVersion))
+	checkStringContains(t, output, "deprecated: use release instead\n")
+	checkStringContains(t, output, "group:\n    id: manage\n    title: 'Management Commands:'\n")
+	checkStringContains(t, output, "valid_args:\n    - prod\n    - staging\n")
+	checkStringContains(t, output, "annotations:\n    since: \"1.2\"\n")
+	checkStringContains(t, output, "    - name: region\n      type: string\n      default_value: eu\n      usage: region to deploy to\n      persistent: true\n      shorthand_deprecated: use --region\n")
+	checkStringContains(t, output, "see_also:\n    - app - The app\n")
+}
+
+func TestGenYamlFlagsAsBefore(t *testing.T) {
+	c := &cobra.Command{Use: "c", Run: emptyRun}
+	c.Flags().StringP("hidden", "x", "", "a hidden flag")
+	assertNoErr(t, c.Flags().MarkHidden("hidden"))
+	c.Flags().StringP("old", "o", "", "a deprecated flag")
+	assertNoErr(t, c.Flags().MarkDeprecated("old", "use --new"))
+	c.Flags().StringP("short", "s", "", "a flag with a deprecated shorthand")
+	assertNoErr(t, c.Flags().MarkShorthandDeprecated("short", "use --short"))
+
+	buf := new(bytes.Buffer)
+	if err := GenYaml(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	// Hidden and deprecated flags are listed, as they were before the schema
+	// was versioned, and marked as such.
+	checkStringContains(t, output, "    - name: hidden\n      shorthand: x\n      type: string\n      usage: a hidden flag\n      hidden: true\n")
+	checkStringContains(t, output, "    - name: old\n      shorthand: o\n      type: string\n      usage: a deprecated flag\n      hidden: true\n      deprecated: use --new\n")
+	// A deprecated shorthand is left out.
+	checkStringContains(t, output, "    - name: short\n      type: string\n      usage: a flag with a deprecated shorthand\n      shorthand_deprecated: use --short\n")
+}
@@ -0,0 +1,191 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The kinds of the errors in the invocation of a command.
+const (
+	ErrorKindUnknownCommand   = "unknown_command"
+	ErrorKindInvalidArgument  = "invalid_argument"
+	ErrorKindInvalidArgs      = "invalid_args"
+	ErrorKindUnknownFlag      = "unknown_flag"
+	ErrorKindAmbiguousFlag    = "ambiguous_flag"
+	ErrorKindInvalidFlagValue = "invalid_flag_value"
+	ErrorKindMissingFlagValue = "missing_flag_value"
+	ErrorKindInvalidFlag      = "invalid_flag"
+	ErrorKindRequiredFlag     = "required_flag"
+	ErrorKindFlagGroup        = "flag_group"
+	ErrorKindNotConfirmed     = "not_confirmed"
+	ErrorKindError            = "error"
+)
+
+const (
+	errorFormatFlagName           = "error-format"
+	errorFormatText               = "text"
+	errorFormatJSON               = "json"
+	configEnvVarSuffixErrorFormat = "ERROR_FORMAT"
+)
+
+// CommandError is an error in the invocation of a command, such as an unknown flag,
+// describing what went wrong to the programs reading the errors.
+type CommandError struct {
+	// Kind is one of the ErrorKind constants.
+	Kind string
+	// Flags are the names, or shorthands, of the flags the error is about.
+	Flags []string
+	// Argument is the positional argument the error is about.
+	Argument string
+	// Suggestions are the commands or flags which may have been meant.
+	Suggestions []string
+	// Err is the error, without the suggestions.
+	Err error
+
+	// details follow the message of Err in the text of the error.
+	details string
+}
+
+// Error returns the message of the error, followed by the suggestions.
+func (e *CommandError) Error() string {
+	return e.Err.Error() + e.details
+}
+
+// Unwrap returns the underlying error.
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorOptions are the options to control the reporting of the errors of the program.
+type ErrorOptions struct {
+	// Format is the format of the errors reported by ExecuteC: "text", the default, or
+	// "json" for a JSON object per error. It is overridden by the <PROGRAM>_ERROR_FORMAT
+	// or COBRA_ERROR_FORMAT environment variables and the '--error-format' flag.
+	Format string
+	// EnableFormatFlag adds the '--error-format' persistent flag to the root command
+	EnableFormatFlag bool
+}
+
+// errorReport is the JSON object an error is reported as.
+type errorReport struct {
+	Kind        string   `json:"kind"`
+	Message     string   `json:"message"`
+	Command     string   `json:"command"`
+	Flags       []string `json:"flags,omitempty"`
+	Argument    string   `json:"argument,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// initErrorFormatFlag adds the '--error-format' persistent flag to the root command c
+// if it is enabled. If c already has such a flag, it will do nothing.
+func (c *Command) initErrorFormatFlag() {
+	if !c.ErrorOptions.EnableFormatFlag {
+		return
+	}
+	if c.PersistentFlags().Lookup(errorFormatFlagName) != nil {
+		return
+	}
+	c.PersistentFlags().String(errorFormatFlagName, errorFormatText, Translate("format of the errors, one of: text|json"))
+	_ = c.PersistentFlags().SetAnnotation(errorFormatFlagName, FlagSetByCobraAnnotation, []string{"true"})
+	_ = c.RegisterFlagCompletionFunc(errorFormatFlagName, FixedCompletions([]string{errorFormatText, errorFormatJSON}, ShellCompDirectiveNoFileComp))
+}
+
+// errorFormat returns the format of the errors of the program of the root command c,
+// run with args. The flag is looked up in args, as errors may occur before it is parsed.
+func (c *Command) errorFormat(args []string) string {
+	if c.ErrorOptions.EnableFormatFlag {
+		for i, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if strings.HasPrefix(arg, "--"+errorFormatFlagName+"=") {
+				return strings.TrimPrefix(arg, "--"+errorFormatFlagName+"=")
+			}
+			if arg == "--"+errorFormatFlagName && i+1 < len(args) {
+				return args[i+1]
+			}
+		}
+	}
+	if v := getEnvConfig(c, configEnvVarSuffixErrorFormat); v != "" {
+		return v
+	}
+	if c.ErrorOptions.Format != "" {
+		return c.ErrorOptions.Format
+	}
+	return errorFormatText
+}
+
+// printJSONError prints err, which occurred running c, as a JSON object to the error
+// output, if the program run with args reports its errors as JSON. It returns whether it did.
+func (c *Command) printJSONError(err error, args []string) bool {
+	if c.Root().errorFormat(args) != errorFormatJSON {
+		return false
+	}
+
+	report := errorReport{Kind: ErrorKindError, Message: err.Error(), Command: c.CommandPath()}
+	// Only the errors created by Cobra are CommandErrors: the errors returned by
+	// pflag are classified here, leaving the errors returned to callers unchanged.
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		cmdErr = flagCommandError(err)
+	}
+	switch {
+	case cmdErr != nil:
+		report.Kind = cmdErr.Kind
+		report.Message = cmdErr.Err.Error()
+		report.Flags = cmdErr.Flags
+		report.Argument = cmdErr.Argument
+		report.Suggestions = cmdErr.Suggestions
+	case errors.Is(err, ErrNotConfirmed):
+		report.Kind = ErrorKindNotConfirmed
+	}
+	b, jsonErr := json.Marshal(report)
+	if jsonErr != nil {
+		return false
+	}
+	c.PrintErrln(string(b))
+	return true
+}
+
+// suggestionsText returns the text suggesting suggestions, following the message of an error.
+func suggestionsText(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n" + Translate("Did you mean this?") + "\n")
+	for _, s := range suggestions {
+		_, _ = fmt.Fprintf(&sb, "\t%v\n", s)
+	}
+	return sb.String()
+}
+
+// argError returns the error of kind about the positional argument arg of c. Its message
+// is built from format, given arg, the path of c and an empty string in place of the
+// suggestions for arg, which are added to the error.
+func (c *Command) argError(kind, format, arg string) error {
+	suggestions := c.commandSuggestions(arg)
+	return &CommandError{
+		Kind:        kind,
+		Argument:    arg,
+		Suggestions: suggestions,
+		Err:         fmt.Errorf(format, arg, c.CommandPath(), ""),
+		details:     suggestionsText(suggestions),
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func jsonErrorsTestTree(t *testing.T) *Command {
+	rootCmd := &Command{Use: "root", Run: emptyRun, ErrorOptions: ErrorOptions{EnableFormatFlag: true}}
+	childCmd := &Command{Use: "child", Args: ExactArgs(1), Run: emptyRun}
+	childCmd.Flags().Int("count", 0, "count")
+	childCmd.Flags().String("name", "", "name")
+	assertNoErr(t, childCmd.MarkFlagRequired("name"))
+	rootCmd.AddCommand(childCmd)
+	return rootCmd
+}
+
+func executeForJSONError(t *testing.T, root *Command, args ...string) (errorReport, string) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SetArgs(args)
+	if err := root.Execute(); err == nil {
+		t.Fatal("Expected an error")
+	}
+	var report errorReport
+	if err := json.Unmarshal(stderr.Bytes(), &report); err != nil {
+		t.Fatalf("Expected a JSON error, got %q: %v", stderr.String(), err)
+	}
+	return report, stdout.String()
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected errorReport
+	}{
+		{
+			name: "Unknown command",
+			args: []string{"chld", "--error-format", "json"},
+			expected: errorReport{
+				Kind:        ErrorKindUnknownCommand,
+				Message:     `unknown command "chld" for "root"`,
+				Command:     "root",
+				Argument:    "chld",
+				Suggestions: []string{"child"},
+			},
+		},
+		{
+			name: "Unknown flag",
+			args: []string{"child", "--nme", "x", "--error-format=json"},
+			expected: errorReport{
+				Kind:        ErrorKindUnknownFlag,
+				Message:     "unknown flag: --nme",
+				Command:     "root child",
+				Flags:       []string{"nme"},
+				Suggestions: []string{"--name"},
+			},
+		},
+		{
+			name: "Invalid flag value",
+			args: []string{"--error-format=json", "child", "--count", "x"},
+			expected: errorReport{
+				Kind:    ErrorKindInvalidFlagValue,
+				Message: `invalid argument "x" for "--count" flag: strconv.ParseInt: parsing "x": invalid syntax`,
+				Command: "root child",
+				Flags:   []string{"count"},
+			},
+		},
+		{
+			name: "Missing flag value",
+			args: []string{"--error-format=json", "child", "--name"},
+			expected: errorReport{
+				Kind:    ErrorKindMissingFlagValue,
+				Message: "flag needs an argument: --name",
+				Command: "root child",
+				Flags:   []string{"name"},
+			},
+		},
+		{
+			name: "Required flag",
+			args: []string{"--error-format=json", "child", "a"},
+			expected: errorReport{
+				Kind:    ErrorKindRequiredFlag,
+				Message: `required flag(s) "name" not set`,
+				Command: "root child",
+				Flags:   []string{"name"},
+			},
+		},
+		{
+			name: "Invalid args",
+			args: []string{"--error-format=json", "child", "--name", "x"},
+			expected: errorReport{
+				Kind:    ErrorKindInvalidArgs,
+				Message: "accepts 1 arg(s), received 0",
+				Command: "root child",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, stdout := executeForJSONError(t, jsonErrorsTestTree(t), tt.args...)
+			if !reflect.DeepEqual(report, tt.expected) {
+				t.Errorf("Expected %+v\nGot %+v", tt.expected, report)
+			}
+			if stdout != "" {
+				t.Errorf("Expected no usage, got %q", stdout)
+			}
+		})
+	}
+}
+
+func TestJSONErrorsFromEnvironment(t *testing.T) {
+	os.Setenv("ROOT_ERROR_FORMAT", "json")
+	defer os.Unsetenv("ROOT_ERROR_FORMAT")
+
+	rootCmd := jsonErrorsTestTree(t)
+	rootCmd.ErrorOptions.EnableFormatFlag = false
+	report, _ := executeForJSONError(t, rootCmd, "child", "a", "b")
+	if report.Kind != ErrorKindInvalidArgs || report.Message != "accepts 1 arg(s), received 2" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestJSONErrorsFromOptions(t *testing.T) {
+	rootCmd := &Command{Use: "root", ErrorOptions: ErrorOptions{Format: errorFormatJSON}}
+	rootCmd.RunE = func(*Command, []string) error { return errors.New("boom") }
+
+	report, _ := executeForJSONError(t, rootCmd)
+	expected := errorReport{Kind: ErrorKindError, Message: "boom", Command: "root"}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Expected %+v\nGot %+v", expected, report)
+	}
+}
+
+func TestJSONErrorsSilenced(t *testing.T) {
+	rootCmd := &Command{Use: "root", SilenceErrors: true, ErrorOptions: ErrorOptions{Format: errorFormatJSON}}
+	rootCmd.RunE = func(*Command, []string) error { return errors.New("boom") }
+
+	output, err := executeCommand(rootCmd)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if output != "" {
+		t.Errorf("Expected no output, got %q", output)
+	}
+}
+
+func TestErrorsKeptForCallers(t *testing.T) {
+	errCustom := errors.New("custom")
+	var flagErr error
+	rootCmd := &Command{
+		Use:  "root",
+		Args: func(*Command, []string) error { return errCustom },
+		Run:  emptyRun,
+	}
+	rootCmd.SetFlagErrorFunc(func(_ *Command, err error) error {
+		flagErr = err
+		return err
+	})
+
+	// The error of a custom Args validator is returned as is.
+	if _, err := executeCommand(rootCmd, "a"); err != errCustom {
+		t.Errorf("Expected the error of the Args validator, got %#v", err)
+	}
+
+	// The errors of pflag are given to the FlagErrorFunc as is.
+	if _, err := executeCommand(rootCmd, "--unknown"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	var cmdErr *CommandError
+	if flagErr == nil || errors.As(flagErr, &cmdErr) || flagErr.Error() != "unknown flag: --unknown" {
+		t.Errorf("Expected the error of pflag, got %#v", flagErr)
+	}
+}
+
+func TestCommandErrorKeepsMessage(t *testing.T) {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+
+	_, err := executeCommand(rootCmd, "extra")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Kind != ErrorKindUnknownCommand || cmdErr.Argument != "extra" {
+		t.Fatalf("Expected an unknown command error, got %#v", err)
+	}
+	if err.Error() != `unknown command "extra" for "root"` {
+		t.Errorf("Unexpected message: %q", err.Error())
+	}
+}
@@ -158,7 +158,11 @@ func validateRequiredFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(unset)
-		return fmt.Errorf("if any flags in the group [%v] are set they must all be set; missing %v", flagList, unset)
+		return &CommandError{
+			Kind:  ErrorKindFlagGroup,
+			Flags: unset,
+			Err:   fmt.Errorf(Translate("if any flags in the group [%v] are set they must all be set; missing %v"), flagList, unset),
+		}
 	}
 
 	return nil
@@ -180,7 +184,11 @@ func validateOneRequiredFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(set)
-		return fmt.Errorf("at least one of the flags in the group [%v] is required", flagList)
+		return &CommandError{
+			Kind:  ErrorKindFlagGroup,
+			Flags: strings.Split(flagList, " "),
+			Err:   fmt.Errorf(Translate("at least one of the flags in the group [%v] is required"), flagList),
+		}
 	}
 	return nil
 }
@@ -201,7 +209,11 @@ func validateExclusiveFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(set)
-		return fmt.Errorf("if any flags in the group [%v] are set none of the others can be; %v were all set", flagList, set)
+		return &CommandError{
+			Kind:  ErrorKindFlagGroup,
+			Flags: set,
+			Err:   fmt.Errorf(Translate("if any flags in the group [%v] are set none of the others can be; %v were all set"), flagList, set),
+		}
 	}
 	return nil
 }
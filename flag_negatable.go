@@ -0,0 +1,110 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// negatableFlag is the annotation marking a boolean flag which also accepts --no-<name>.
+	negatableFlag = "cobra_annotation_negatable"
+
+	negatedFlagPrefix       = "no-"
+	negatableFlagNamePrefix = "[no-]"
+)
+
+// MarkFlagNegatable makes the named boolean flag also accept --no-<name>, which sets it to false.
+// The flag is shown once in help, as --[no-]<name>.
+func (c *Command) MarkFlagNegatable(name string) error {
+	return MarkFlagNegatable(c.Flags(), name)
+}
+
+// MarkPersistentFlagNegatable makes the named persistent boolean flag also accept --no-<name>,
+// which sets it to false. The flag is shown once in help, as --[no-]<name>.
+func (c *Command) MarkPersistentFlagNegatable(name string) error {
+	return MarkFlagNegatable(c.PersistentFlags(), name)
+}
+
+// MarkFlagNegatable makes the named boolean flag also accept --no-<name>, which sets it to false.
+// The flag is shown once in help, as --[no-]<name>.
+func MarkFlagNegatable(flags *pflag.FlagSet, name string) error {
+	f := flags.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("flag %q does not exist", name)
+	}
+	if f.Value.Type() != "bool" {
+		return fmt.Errorf("flag %q is not a boolean flag", name)
+	}
+	return flags.SetAnnotation(name, negatableFlag, []string{"true"})
+}
+
+// IsFlagNegatable returns whether flag was marked negatable by MarkFlagNegatable.
+func IsFlagNegatable(flag *pflag.Flag) bool {
+	_, negatable := flag.Annotations[negatableFlag]
+	return negatable
+}
+
+// FormatFlagUsages returns the usages of flags wrapped to cols columns, as
+// FlagUsagesWrapped does, with the negatable flags shown as --[no-]<name>
+// and the usage strings translated by Translate.
+func FormatFlagUsages(flags *pflag.FlagSet, cols int) string {
+	rewritten := false
+	flags.VisitAll(func(f *pflag.Flag) {
+		rewritten = rewritten || IsFlagNegatable(f) || Translate(f.Usage) != f.Usage
+	})
+	if !rewritten {
+		return flags.FlagUsagesWrapped(cols)
+	}
+
+	// Rewritten copies of the flags are added in the order they are visited in.
+	display := pflag.NewFlagSet("", pflag.ContinueOnError)
+	display.SortFlags = false
+	flags.VisitAll(func(f *pflag.Flag) {
+		shown := *f
+		shown.Usage = Translate(f.Usage)
+		if IsFlagNegatable(f) {
+			shown.Name = negatableFlagNamePrefix + f.Name
+		}
+		display.AddFlag(&shown)
+	})
+	return display.FlagUsagesWrapped(cols)
+}
+
+// lookupNegatedFlag returns the negatable flag of fs negated by name, given
+// as no-<name>, unless fs has a flag named name.
+func lookupNegatedFlag(fs *pflag.FlagSet, name string) *pflag.Flag {
+	if !strings.HasPrefix(name, negatedFlagPrefix) || fs.Lookup(name) != nil {
+		return nil
+	}
+	if f := fs.Lookup(strings.TrimPrefix(name, negatedFlagPrefix)); f != nil && IsFlagNegatable(f) {
+		return f
+	}
+	return nil
+}
+
+// expandNegatedFlags returns a copy of args in which the negated flags
+// --no-<name> are replaced by --<name>=false.
+func expandNegatedFlags(args []string, fs *pflag.FlagSet) ([]string, error) {
+	return rewriteLongFlags(args, fs, func(name, value string) (string, error) {
+		if f := lookupNegatedFlag(fs, name); f != nil && value == "" {
+			return "--" + f.Name + "=false", nil
+		}
+		return "--" + name + value, nil
+	})
+}
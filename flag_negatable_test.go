@@ -0,0 +1,129 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func negatableTestCmd() (*Command, *bool) {
+	cache := true
+	c := &Command{Use: "c", Args: NoArgs, Run: emptyRun}
+	c.Flags().BoolVarP(&cache, "cache", "c", true, "use the cache")
+	if err := c.MarkFlagNegatable("cache"); err != nil {
+		panic(err)
+	}
+	return c, &cache
+}
+
+func TestNegatableFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+		changed  bool
+	}{
+		{name: "Default", args: nil, expected: true, changed: false},
+		{name: "Negated", args: []string{"--no-cache"}, expected: false, changed: true},
+		{name: "Set", args: []string{"--cache"}, expected: true, changed: true},
+		{name: "Negated then set", args: []string{"--no-cache", "--cache"}, expected: true, changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, cache := negatableTestCmd()
+			if _, err := executeCommand(c, tt.args...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *cache != tt.expected {
+				t.Errorf("Expected cache to be %v, got %v", tt.expected, *cache)
+			}
+			if changed := c.Flags().Changed("cache"); changed != tt.changed {
+				t.Errorf("Expected Changed to be %v, got %v", tt.changed, changed)
+			}
+		})
+	}
+}
+
+func TestNegatableFlagValueRejected(t *testing.T) {
+	c, _ := negatableTestCmd()
+	_, err := executeCommand(c, "--no-cache=true")
+	if err == nil || !strings.HasPrefix(err.Error(), "unknown flag: --no-cache") {
+		t.Errorf("Expected an unknown flag error, got %v", err)
+	}
+}
+
+func TestNegatableFlagBeforeSubcommand(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().Bool("cache", true, "use the cache")
+	assertNoErr(t, rootCmd.MarkPersistentFlagNegatable("cache"))
+	childCmd := &Command{Use: "child", Args: NoArgs, Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	if _, err := executeCommand(rootCmd, "--no-cache", "child"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := childCmd.Flags().GetBool("cache"); v {
+		t.Error("Expected the inherited flag to be negated")
+	}
+}
+
+func TestNegatableFlagMutuallyExclusive(t *testing.T) {
+	c, _ := negatableTestCmd()
+	c.Flags().Bool("offline", false, "offline")
+	c.MarkFlagsMutuallyExclusive("cache", "offline")
+
+	_, err := executeCommand(c, "--no-cache", "--offline")
+	if err == nil || !strings.Contains(err.Error(), "[cache offline] were all set") {
+		t.Errorf("Expected a mutually exclusive error, got %v", err)
+	}
+}
+
+func TestNegatableFlagHelp(t *testing.T) {
+	c, _ := negatableTestCmd()
+	output, err := executeCommand(c, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "-c, --[no-]cache   use the cache (default true)")
+	checkStringOmits(t, output, "--cache")
+	checkStringOmits(t, output, "--no-cache")
+}
+
+func TestNegatableFlagCompletion(t *testing.T) {
+	c, _ := negatableTestCmd()
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(c)
+
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "c", "--")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--cache\n")
+	checkStringContains(t, output, "--no-cache\n")
+}
+
+func TestMarkFlagNegatableErrors(t *testing.T) {
+	c := &Command{Use: "c"}
+	c.Flags().String("name", "", "name")
+
+	if err := c.MarkFlagNegatable("name"); err == nil {
+		t.Error("Expected an error for a non-boolean flag")
+	}
+	if err := c.MarkFlagNegatable("missing"); err == nil {
+		t.Error("Expected an error for a missing flag")
+	}
+}
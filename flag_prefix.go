@@ -0,0 +1,126 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// lookupFlag returns the flag of fs named name, or negated by name. When EnableFlagPrefixMatching is set,
+// name may also be a unique prefix of the name of a visible flag; the names of all the
+// flags it is a prefix of are returned when it is ambiguous.
+func lookupFlag(fs *flag.FlagSet, name string) (*flag.Flag, []string) {
+	if f := fs.Lookup(name); f != nil {
+		return f, nil
+	}
+	if f := lookupNegatedFlag(fs, name); f != nil {
+		return f, nil
+	}
+	if !EnableFlagPrefixMatching || name == "" {
+		return nil, nil
+	}
+
+	prefix := string(fs.GetNormalizeFunc()(fs, name))
+	var found *flag.Flag
+	var candidates []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if !f.Hidden && strings.HasPrefix(f.Name, prefix) {
+			found = f
+			candidates = append(candidates, f.Name)
+		}
+	})
+	if len(candidates) != 1 {
+		return nil, candidates
+	}
+	return found, nil
+}
+
+// expandFlagPrefixes returns a copy of args in which the long flags abbreviated
+// to a unique prefix are replaced by the full flag names of fs. An error is returned
+// when an abbreviation is ambiguous. Flags unknown to fs are left for the parser to report.
+func expandFlagPrefixes(args []string, fs *flag.FlagSet) ([]string, error) {
+	return rewriteLongFlags(args, fs, func(name, value string) (string, error) {
+		f, candidates := lookupFlag(fs, name)
+		if f == nil {
+			if len(candidates) > 1 {
+				suggestions := make([]string, len(candidates))
+				for i, candidate := range candidates {
+					suggestions[i] = "--" + candidate
+				}
+				return "", &CommandError{
+					Kind:        ErrorKindAmbiguousFlag,
+					Flags:       []string{name},
+					Suggestions: suggestions,
+					Err:         fmt.Errorf(Translate("ambiguous flag: --%s could match %s"), name, strings.Join(suggestions, ", ")),
+				}
+			}
+			return "--" + name + value, nil
+		}
+		return "--" + f.Name + value, nil
+	})
+}
+
+// rewriteLongFlags returns a copy of args in which each long flag is replaced by
+// the result of rewrite, given the name of the flag and its "=value" suffix if any.
+// Flag values and the arguments after "--" are not rewritten.
+func rewriteLongFlags(args []string, fs *flag.FlagSet, rewrite func(name, value string) (string, error)) ([]string, error) {
+	rewritten := make([]string, len(args))
+	copy(rewritten, args)
+
+	for i := 0; i < len(rewritten); i++ {
+		s := rewritten[i]
+		switch {
+		case s == "--":
+			// "--" terminates the flags
+			return rewritten, nil
+		case strings.HasPrefix(s, "--"):
+			name, value := s[2:], ""
+			if eq := strings.Index(name, "="); eq >= 0 {
+				name, value = name[:eq], name[eq:]
+			}
+			arg, err := rewrite(name, value)
+			if err != nil {
+				return nil, err
+			}
+			rewritten[i] = arg
+			if strings.Contains(arg, "=") {
+				continue
+			}
+			if f := fs.Lookup(strings.TrimPrefix(arg, "--")); f != nil && f.NoOptDefVal == "" {
+				// The next argument is the value of the flag.
+				i++
+			}
+		case strings.HasPrefix(s, "-") && len(s) > 1 && !strings.Contains(s, "="):
+			// In '-abc', the last shorthand expecting a value takes the next argument
+			// when nothing follows it.
+			for j := 1; j < len(s); j++ {
+				f := fs.ShorthandLookup(s[j : j+1])
+				if f == nil {
+					break
+				}
+				if f.NoOptDefVal == "" {
+					if j == len(s)-1 {
+						i++
+					}
+					break
+				}
+			}
+		}
+	}
+	return rewritten, nil
+}
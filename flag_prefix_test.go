@@ -0,0 +1,125 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlagPrefixMatching(t *testing.T) {
+	defer func(ov bool) { EnableFlagPrefixMatching = ov }(EnableFlagPrefixMatching)
+	EnableFlagPrefixMatching = true
+
+	var verbose bool
+	var name, config string
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().StringVar(&config, "config", "", "config file")
+	childCmd := &Command{
+		Use:  "child",
+		Args: ExactArgs(1),
+		Run:  emptyRun,
+	}
+	childCmd.Flags().BoolVar(&verbose, "verbose", false, "verbose")
+	childCmd.Flags().StringVarP(&name, "name", "n", "", "name")
+	rootCmd.AddCommand(childCmd)
+
+	_, err := executeCommand(rootCmd, "--conf", "c.yaml", "child", "--verb", "--na=x", "arg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !verbose || name != "x" || config != "c.yaml" {
+		t.Errorf("Expected abbreviated flags to be set, got verbose=%v name=%q config=%q", verbose, name, config)
+	}
+}
+
+func TestFlagPrefixMatchingAmbiguous(t *testing.T) {
+	defer func(ov bool) { EnableFlagPrefixMatching = ov }(EnableFlagPrefixMatching)
+	EnableFlagPrefixMatching = true
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().Bool("verbose", false, "verbose")
+	rootCmd.Flags().Bool("version-check", false, "check version")
+
+	_, err := executeCommand(rootCmd, "--ver")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected := "ambiguous flag: --ver could match --verbose, --version-check"
+	if !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("Expected error %q, got %q", expected, err.Error())
+	}
+}
+
+func TestFlagPrefixMatchingDisabled(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().Bool("verbose", false, "verbose")
+
+	_, err := executeCommand(rootCmd, "--verb")
+	if err == nil || !strings.HasPrefix(err.Error(), "unknown flag: --verb") {
+		t.Errorf("Expected an unknown flag error, got %v", err)
+	}
+}
+
+func TestExpandFlagPrefixes(t *testing.T) {
+	defer func(ov bool) { EnableFlagPrefixMatching = ov }(EnableFlagPrefixMatching)
+	EnableFlagPrefixMatching = true
+
+	c := &Command{Use: "c"}
+	c.Flags().StringP("name", "n", "", "name")
+	c.Flags().BoolP("all", "a", false, "all")
+	c.Flags().Bool("secret", false, "secret")
+	_ = c.Flags().MarkHidden("secret")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "Values are not expanded",
+			args:     []string{"--na", "--al", "--al"},
+			expected: []string{"--name", "--al", "--all"},
+		},
+		{
+			name:     "Shorthand values are not expanded",
+			args:     []string{"-an", "--al", "--al"},
+			expected: []string{"-an", "--al", "--all"},
+		},
+		{
+			name:     "Arguments after -- are not expanded",
+			args:     []string{"--al", "--", "--na"},
+			expected: []string{"--all", "--", "--na"},
+		},
+		{
+			name:     "Hidden flags are not abbreviated",
+			args:     []string{"--sec", "--secret"},
+			expected: []string{"--sec", "--secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandFlagPrefixes(tt.args, c.Flags())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
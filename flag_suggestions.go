@@ -0,0 +1,215 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// The prefixes of the errors returned by pflag for flags it does not know,
+// for flags given without a value and for malformed flags.
+const (
+	unknownFlagErrPrefix      = "unknown flag: --"
+	unknownShorthandErrPrefix = "unknown shorthand flag: '"
+	missingFlagValueErrPrefix = "flag needs an argument: "
+	badFlagSyntaxErrPrefix    = "bad flag syntax: "
+)
+
+// invalidFlagValueErrRegexp matches the errors returned by pflag for invalid flag values,
+// capturing the name of the flag.
+var invalidFlagValueErrRegexp = regexp.MustCompile(`^invalid argument ".*" for "(?:-., )?--([^"]+)" flag: `)
+
+// flagSuggestion is a flag suggested for a mistyped flag.
+type flagSuggestion struct {
+	flag     *flag.Flag
+	distance int
+	// cmd is the command defining the flag, if it is not the command being executed.
+	cmd *Command
+}
+
+// flagError returns err, returned by pflag parsing the flags of c, followed by the
+// suggestions of similar flags when it reports a flag unknown to c. Otherwise err
+// is returned as is.
+func (c *Command) flagError(err error) error {
+	cmdErr := flagCommandError(err)
+	if cmdErr == nil || cmdErr.Kind != ErrorKindUnknownFlag || c.flagSuggestionsDisabled() {
+		return err
+	}
+
+	var suggestions []flagSuggestion
+	if strings.HasPrefix(err.Error(), unknownShorthandErrPrefix) {
+		suggestions = c.shorthandSuggestionsFor(cmdErr.Flags[0])
+	} else {
+		suggestions = c.flagSuggestionsFor(cmdErr.Flags[0])
+	}
+	if len(suggestions) == 0 {
+		return err
+	}
+
+	for _, s := range suggestions {
+		name := "--" + s.flag.Name
+		if s.distance < 0 {
+			name = "-" + s.flag.Shorthand
+		}
+		if s.cmd != nil {
+			name = fmt.Sprintf(Translate("%s (flag of %q)"), name, s.cmd.CommandPath())
+		}
+		cmdErr.Suggestions = append(cmdErr.Suggestions, name)
+	}
+	cmdErr.details = suggestionsText(cmdErr.Suggestions)
+	return cmdErr
+}
+
+// flagCommandError returns the CommandError describing err, if it is one of the
+// errors returned by pflag parsing flags, or nil.
+func flagCommandError(err error) *CommandError {
+	cmdErr := &CommandError{Err: err}
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, unknownFlagErrPrefix):
+		cmdErr.Kind = ErrorKindUnknownFlag
+		cmdErr.Flags = []string{strings.TrimPrefix(msg, unknownFlagErrPrefix)}
+	case strings.HasPrefix(msg, unknownShorthandErrPrefix) && len(msg) > len(unknownShorthandErrPrefix):
+		cmdErr.Kind = ErrorKindUnknownFlag
+		cmdErr.Flags = []string{msg[len(unknownShorthandErrPrefix) : len(unknownShorthandErrPrefix)+1]}
+	case strings.HasPrefix(msg, missingFlagValueErrPrefix):
+		cmdErr.Kind = ErrorKindMissingFlagValue
+		name := strings.TrimPrefix(msg, missingFlagValueErrPrefix)
+		if strings.HasPrefix(name, "--") {
+			cmdErr.Flags = []string{name[2:]}
+		} else if len(name) > 1 {
+			cmdErr.Flags = []string{name[1:2]}
+		}
+	case strings.HasPrefix(msg, badFlagSyntaxErrPrefix):
+		cmdErr.Kind = ErrorKindInvalidFlag
+	default:
+		m := invalidFlagValueErrRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			return nil
+		}
+		cmdErr.Kind = ErrorKindInvalidFlagValue
+		cmdErr.Flags = []string{m[1]}
+	}
+	return cmdErr
+}
+
+// flagSuggestionsDisabled returns whether suggestions are disabled on c
+// or on any of its parents.
+func (c *Command) flagSuggestionsDisabled() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.DisableSuggestions {
+			return true
+		}
+	}
+	return false
+}
+
+// flagSuggestionsMinimumDistance returns the SuggestionsMinimumDistance of
+// the nearest command setting one, defaulting to 2.
+func (c *Command) flagSuggestionsMinimumDistance() int {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestionsMinimumDistance > 0 {
+			return p.SuggestionsMinimumDistance
+		}
+	}
+	return 2
+}
+
+// flagSuggestionsFor returns the flags similar to the flag typedName, given
+// without its leading dashes. The flags of c are suggested first, followed by
+// the local flags of its sibling and child commands.
+func (c *Command) flagSuggestionsFor(typedName string) []flagSuggestion {
+	maxDistance := c.flagSuggestionsMinimumDistance()
+	suggest := func(x *Command, fs *flag.FlagSet) []flagSuggestion {
+		var found []flagSuggestion
+		fs.VisitAll(func(f *flag.Flag) {
+			if f.Hidden || len(f.Deprecated) != 0 {
+				return
+			}
+			distance := ld(typedName, f.Name, true)
+			if distance <= maxDistance || strings.HasPrefix(strings.ToLower(f.Name), strings.ToLower(typedName)) {
+				found = append(found, flagSuggestion{flag: f, distance: distance, cmd: x})
+			}
+		})
+		sortFlagSuggestions(found)
+		return found
+	}
+
+	suggestions := suggest(nil, c.Flags())
+	for _, other := range c.flagSuggestionCommands() {
+		for _, s := range suggest(other, other.LocalNonPersistentFlags()) {
+			if !hasFlagSuggestion(suggestions, s.flag.Name) {
+				suggestions = append(suggestions, s)
+			}
+		}
+	}
+	return suggestions
+}
+
+// shorthandSuggestionsFor returns the flags of the sibling and child commands
+// of c which have the shorthand typed by the user.
+func (c *Command) shorthandSuggestionsFor(shorthand string) []flagSuggestion {
+	var suggestions []flagSuggestion
+	for _, other := range c.flagSuggestionCommands() {
+		if f := other.LocalNonPersistentFlags().ShorthandLookup(shorthand); f != nil && !f.Hidden && len(f.Deprecated) == 0 {
+			suggestions = append(suggestions, flagSuggestion{flag: f, distance: -1, cmd: other})
+		}
+	}
+	return suggestions
+}
+
+// flagSuggestionCommands returns the available sibling and child commands of c,
+// whose flags may have been meant when an unknown flag is given to c.
+func (c *Command) flagSuggestionCommands() []*Command {
+	var cmds []*Command
+	if c.HasParent() {
+		for _, sibling := range c.Parent().Commands() {
+			if sibling != c && sibling.IsAvailableCommand() {
+				cmds = append(cmds, sibling)
+			}
+		}
+	}
+	for _, child := range c.Commands() {
+		if child.IsAvailableCommand() {
+			cmds = append(cmds, child)
+		}
+	}
+	return cmds
+}
+
+// sortFlagSuggestions orders suggestions from the closest to the farthest match.
+func sortFlagSuggestions(suggestions []flagSuggestion) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].flag.Name < suggestions[j].flag.Name
+	})
+}
+
+// hasFlagSuggestion returns whether a flag named name is already suggested.
+func hasFlagSuggestion(suggestions []flagSuggestion, name string) bool {
+	for _, s := range suggestions {
+		if s.flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
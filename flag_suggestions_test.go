@@ -0,0 +1,123 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func flagSuggestionsTestTree() (*Command, *Command) {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().StringP("namespace", "n", "", "namespace")
+	childCmd.Flags().Bool("verbose", false, "verbose")
+	otherCmd := &Command{Use: "other", Run: emptyRun}
+	otherCmd.Flags().BoolP("recursive", "r", false, "recursive")
+	otherCmd.Flags().Bool("secret", false, "secret")
+	_ = otherCmd.Flags().MarkHidden("secret")
+	rootCmd.AddCommand(childCmd, otherCmd)
+	return rootCmd, childCmd
+}
+
+func TestFlagSuggestions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Typo of a local flag",
+			args:     []string{"child", "--namspace", "x"},
+			expected: "\n\nDid you mean this?\n\t--namespace\n",
+		},
+		{
+			name:     "Prefix of a local flag",
+			args:     []string{"child", "--verb"},
+			expected: "\n\nDid you mean this?\n\t--verbose\n",
+		},
+		{
+			name:     "Flag of a sibling command",
+			args:     []string{"child", "--recursive"},
+			expected: "\n\nDid you mean this?\n\t--recursive (flag of \"root other\")\n",
+		},
+		{
+			name:     "Shorthand of a sibling command",
+			args:     []string{"child", "-r"},
+			expected: "\n\nDid you mean this?\n\t-r (flag of \"root other\")\n",
+		},
+		{
+			name:     "Flag of a child command",
+			args:     []string{"--namespace", "x"},
+			expected: "\n\nDid you mean this?\n\t--namespace (flag of \"root child\")\n",
+		},
+		{
+			name:     "Hidden flags are not suggested",
+			args:     []string{"child", "--secret"},
+			expected: "",
+		},
+		{
+			name:     "Nothing similar",
+			args:     []string{"child", "--xyzzy"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd, _ := flagSuggestionsTestTree()
+			_, err := executeCommand(rootCmd, tt.args...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			got := err.Error()
+			if i := strings.Index(got, "\n"); i >= 0 {
+				got = got[i:]
+			} else {
+				got = ""
+			}
+			if got != tt.expected {
+				t.Errorf("Expected suggestions: %q\nGot: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFlagSuggestionsDisabled(t *testing.T) {
+	rootCmd, _ := flagSuggestionsTestTree()
+	rootCmd.DisableSuggestions = true
+
+	_, err := executeCommand(rootCmd, "child", "--namspace", "x")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if got := err.Error(); got != "unknown flag: --namspace" {
+		t.Errorf("Expected no suggestions, got: %q", got)
+	}
+}
+
+func TestFlagSuggestionsWrapError(t *testing.T) {
+	rootCmd, childCmd := flagSuggestionsTestTree()
+	var flagErr error
+	childCmd.SetFlagErrorFunc(func(c *Command, err error) error {
+		flagErr = err
+		return err
+	})
+
+	_, _ = executeCommand(rootCmd, "child", "--namspace", "x")
+	if flagErr == nil || !strings.HasPrefix(flagErr.Error(), "unknown flag: --namspace\n\nDid you mean this?") {
+		t.Errorf("Expected the flag error func to receive suggestions, got: %v", flagErr)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	helpSearchFlagName  = "search"
+	helpSearchFlagUsage = "list the commands whose help mentions the given term"
+)
+
+// Weights of the places a search term can be found in, from most to least relevant.
+const (
+	searchScoreName        = 100
+	searchScoreNamePrefix  = 60
+	searchScoreAlias       = 50
+	searchScoreNamePart    = 40
+	searchScoreAliasPrefix = 30
+	searchScoreShort       = 20
+	searchScoreFlagName    = 15
+	searchScoreLong        = 10
+	searchScoreExample     = 5
+	searchScoreFlagUsage   = 5
+)
+
+// HelpSearchResult is a command matching the term given to SearchHelp.
+type HelpSearchResult struct {
+	// Command is the matching command.
+	Command *Command
+	// Score ranks the result: the higher the score, the better the match.
+	Score int
+}
+
+// SearchHelp searches the names, aliases, descriptions, examples and flags of all
+// available commands below c for term, ignoring case. When term has several words,
+// a command must match each of them. The results are sorted from best to worst match.
+func (c *Command) SearchHelp(term string) []HelpSearchResult {
+	words := strings.Fields(strings.ToLower(term))
+	if len(words) == 0 {
+		return nil
+	}
+
+	var results []HelpSearchResult
+	var search func(*Command)
+	search = func(x *Command) {
+		for _, sub := range x.Commands() {
+			if !sub.IsAvailableCommand() && !sub.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			if score := sub.helpSearchScore(words); score > 0 {
+				results = append(results, HelpSearchResult{Command: sub, Score: score})
+			}
+			search(sub)
+		}
+	}
+	search(c)
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Command.CommandPath() < results[j].Command.CommandPath()
+	})
+	return results
+}
+
+// helpSearchScore returns how well c matches all the lower-case words,
+// or 0 if one of the words is not found.
+func (c *Command) helpSearchScore(words []string) int {
+	total := 0
+	for _, word := range words {
+		score := c.helpSearchWordScore(word)
+		if score == 0 {
+			return 0
+		}
+		total += score
+	}
+	return total
+}
+
+// helpSearchWordScore returns how well c matches a single lower-case word.
+func (c *Command) helpSearchWordScore(word string) int {
+	score := 0
+	name := strings.ToLower(c.Name())
+	switch {
+	case name == word:
+		score += searchScoreName
+	case strings.HasPrefix(name, word):
+		score += searchScoreNamePrefix
+	case strings.Contains(name, word):
+		score += searchScoreNamePart
+	}
+	for _, alias := range c.Aliases {
+		alias = strings.ToLower(alias)
+		if alias == word {
+			score += searchScoreAlias
+			break
+		}
+		if strings.HasPrefix(alias, word) {
+			score += searchScoreAliasPrefix
+			break
+		}
+	}
+	if containsFold(c.Short, word) {
+		score += searchScoreShort
+	}
+	if containsFold(c.Long, word) {
+		score += searchScoreLong
+	}
+	if containsFold(c.Example, word) {
+		score += searchScoreExample
+	}
+
+	flagNameFound, flagUsageFound := false, false
+	c.LocalFlags().VisitAll(func(f *flag.Flag) {
+		if f.Hidden {
+			return
+		}
+		if containsFold(f.Name, word) {
+			flagNameFound = true
+		}
+		if containsFold(f.Usage, word) {
+			flagUsageFound = true
+		}
+	})
+	if flagNameFound {
+		score += searchScoreFlagName
+	}
+	if flagUsageFound {
+		score += searchScoreFlagUsage
+	}
+	return score
+}
+
+// containsFold reports whether the lower-case word is within s, ignoring case.
+func containsFold(s, word string) bool {
+	return strings.Contains(strings.ToLower(s), word)
+}
+
+// printHelpSearch prints the commands below c matching term, best match first.
+func (c *Command) printHelpSearch(out *Command, term string) {
+	results := c.SearchHelp(term)
+	if len(results) == 0 {
+		out.Printf(Translate("No commands match %q.\n"), term)
+		return
+	}
+
+	padding := 0
+	for _, result := range results {
+		if l := len(result.Command.CommandPath()); l > padding {
+			padding = l
+		}
+	}
+	out.Printf(Translate("Commands matching %q:\n"), term)
+	for _, result := range results {
+		out.Printf("  %s %s\n", rpad(result.Command.CommandPath(), padding), result.Command.Short)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"testing"
+)
+
+func setupHelpSearchTest() *Command {
+	rootCmd := &Command{Use: "tool", Run: emptyRun}
+	userCmd := &Command{Use: "user", Short: "Manage users"}
+	deleteCmd := &Command{Use: "delete", Aliases: []string{"rm"}, Short: "Delete a user", Run: emptyRun}
+	addCmd := &Command{Use: "add", Short: "Add a user", Example: "tool user add --admin", Run: emptyRun}
+	addCmd.Flags().Bool("admin", false, "grant administrator rights")
+	purgeCmd := &Command{Use: "purge", Short: "Delete all data", Long: "Purge removes every user and project.", Run: emptyRun}
+	hiddenCmd := &Command{Use: "secret-user", Short: "Hidden user command", Hidden: true, Run: emptyRun}
+	userCmd.AddCommand(deleteCmd, addCmd)
+	rootCmd.AddCommand(userCmd, purgeCmd, hiddenCmd)
+	return rootCmd
+}
+
+func helpSearchPaths(results []HelpSearchResult) []string {
+	var paths []string
+	for _, result := range results {
+		paths = append(paths, result.Command.CommandPath())
+	}
+	return paths
+}
+
+func TestSearchHelp(t *testing.T) {
+	rootCmd := setupHelpSearchTest()
+
+	testcases := []struct {
+		term     string
+		expected []string
+	}{
+		{"user", []string{"tool user", "tool user add", "tool user delete", "tool purge"}},
+		{"DELETE", []string{"tool user delete", "tool purge"}},
+		{"rm", []string{"tool user delete"}},
+		{"admin", []string{"tool user add"}},
+		{"administrator", []string{"tool user add"}},
+		{"delete all", []string{"tool purge"}},
+		{"project", []string{"tool purge"}},
+		{"missing", nil},
+		{"", nil},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.term, func(t *testing.T) {
+			got := helpSearchPaths(rootCmd.SearchHelp(tc.term))
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("Expected %v, got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpSearchCommand(t *testing.T) {
+	rootCmd := setupHelpSearchTest()
+
+	output, err := executeCommand(rootCmd, "help", "--search", "delete")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := `Commands matching "delete":
+  tool user delete Delete a user
+  tool purge       Delete all data
+`
+	if output != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, output)
+	}
+}
+
+func TestHelpSearchCommandNoMatch(t *testing.T) {
+	rootCmd := setupHelpSearchTest()
+
+	output, err := executeCommand(rootCmd, "help", "--search=nothing")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, `No commands match "nothing".`)
+}
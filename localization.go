@@ -0,0 +1,144 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// MessageCatalog translates the messages printed by Cobra and by applications.
+// Messages are identified by their English text; for messages containing formatting
+// verbs, such as "accepts %d arg(s), received %d", the translation must contain
+// the same verbs.
+type MessageCatalog interface {
+	// Translate returns the translation of msg into locale, for example "ja" or "de_DE",
+	// or false if the catalog has no such translation.
+	Translate(locale, msg string) (string, bool)
+}
+
+// mapCatalog is a MessageCatalog holding translations per locale.
+type mapCatalog map[string]map[string]string
+
+func (m mapCatalog) Translate(locale, msg string) (string, bool) {
+	t, ok := m[locale][msg]
+	return t, ok
+}
+
+var (
+	// catalogs are the catalogs added by AddMessageCatalog, most recent first.
+	catalogs []MessageCatalog
+	// translations are the translations added by AddTranslations.
+	translations = mapCatalog{}
+	// locale is the locale set by SetLocale.
+	locale string
+	// lock for reading and writing catalogs, translations and locale
+	localizationMutex = &sync.RWMutex{}
+)
+
+// AddMessageCatalog adds a catalog consulted to translate messages.
+// Catalogs are consulted from the most recently added one, before the
+// translations added by AddTranslations.
+func AddMessageCatalog(catalog MessageCatalog) {
+	localizationMutex.Lock()
+	defer localizationMutex.Unlock()
+	catalogs = append([]MessageCatalog{catalog}, catalogs...)
+}
+
+// AddTranslations adds translations of messages into locale, keyed by the English
+// text of the messages. This can be used both for the messages printed by Cobra
+// and for the Short, Long and Example texts of the application's commands.
+func AddTranslations(locale string, messages map[string]string) {
+	localizationMutex.Lock()
+	defer localizationMutex.Unlock()
+	locale = normalizeLocale(locale)
+	if translations[locale] == nil {
+		translations[locale] = map[string]string{}
+	}
+	for msg, t := range messages {
+		translations[locale][msg] = t
+	}
+}
+
+// SetLocale sets the locale messages are translated into, overriding the environment.
+// An empty locale restores the locale of the environment.
+func SetLocale(l string) {
+	localizationMutex.Lock()
+	defer localizationMutex.Unlock()
+	locale = normalizeLocale(l)
+}
+
+// Locale returns the locale messages are translated into: the locale set by SetLocale,
+// or the locale of the environment given by the LC_ALL, LC_MESSAGES or LANG environment
+// variables. An empty locale means that messages are not translated.
+func Locale() string {
+	localizationMutex.RLock()
+	l := locale
+	localizationMutex.RUnlock()
+	if l != "" {
+		return l
+	}
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := os.Getenv(v); l != "" {
+			return normalizeLocale(l)
+		}
+	}
+	return ""
+}
+
+// normalizeLocale turns a locale such as "de_DE.UTF-8@euro" or "de-DE" into "de_DE".
+// The C and POSIX locales are returned as an empty locale.
+func normalizeLocale(l string) string {
+	if i := strings.IndexAny(l, ".@"); i >= 0 {
+		l = l[:i]
+	}
+	l = strings.ReplaceAll(l, "-", "_")
+	if l == "C" || l == "POSIX" {
+		return ""
+	}
+	return l
+}
+
+// Translate returns the translation of msg into the current Locale, or msg itself
+// if it has no translation.
+func Translate(msg string) string {
+	l := Locale()
+	if l == "" || msg == "" {
+		return msg
+	}
+
+	candidates := []string{l}
+	if i := strings.Index(l, "_"); i > 0 {
+		// Fall back on the language when there is no translation for the region
+		candidates = append(candidates, l[:i])
+	}
+
+	localizationMutex.RLock()
+	defer localizationMutex.RUnlock()
+	for _, catalog := range catalogs {
+		for _, candidate := range candidates {
+			if t, ok := catalog.Translate(candidate, msg); ok {
+				return t
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if t, ok := translations.Translate(candidate, msg); ok {
+			return t
+		}
+	}
+	return msg
+}
@@ -0,0 +1,180 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"os"
+	"testing"
+)
+
+func resetLocalization() {
+	localizationMutex.Lock()
+	defer localizationMutex.Unlock()
+	catalogs = nil
+	translations = mapCatalog{}
+	locale = ""
+}
+
+type testCatalog map[string]string
+
+func (c testCatalog) Translate(locale, msg string) (string, bool) {
+	if locale != "ja" {
+		return "", false
+	}
+	t, ok := c[msg]
+	return t, ok
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	testcases := map[string]string{
+		"ja_JP.UTF-8":      "ja_JP",
+		"de_DE.UTF-8@euro": "de_DE",
+		"de-DE":            "de_DE",
+		"fr":               "fr",
+		"C":                "",
+		"C.UTF-8":          "",
+		"POSIX":            "",
+		"":                 "",
+	}
+	for l, expected := range testcases {
+		if got := normalizeLocale(l); got != expected {
+			t.Errorf("Expected normalizeLocale(%q) to be %q, got %q", l, expected, got)
+		}
+	}
+}
+
+func TestLocaleFromEnvironment(t *testing.T) {
+	defer resetLocalization()
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if orig, ok := os.LookupEnv(v); ok {
+			defer os.Setenv(v, orig)
+		} else {
+			defer os.Unsetenv(v)
+		}
+		os.Unsetenv(v)
+	}
+
+	if got := Locale(); got != "" {
+		t.Errorf("Expected no locale, got %q", got)
+	}
+
+	os.Setenv("LANG", "de_DE.UTF-8")
+	if got := Locale(); got != "de_DE" {
+		t.Errorf("Expected locale de_DE, got %q", got)
+	}
+
+	os.Setenv("LC_ALL", "ja_JP.UTF-8")
+	if got := Locale(); got != "ja_JP" {
+		t.Errorf("Expected LC_ALL to take precedence, got %q", got)
+	}
+
+	SetLocale("fr")
+	if got := Locale(); got != "fr" {
+		t.Errorf("Expected SetLocale to take precedence, got %q", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer resetLocalization()
+
+	AddTranslations("de", map[string]string{"Usage:": "Verwendung:"})
+
+	SetLocale("de_AT.UTF-8")
+	if got := Translate("Usage:"); got != "Verwendung:" {
+		t.Errorf("Expected the language translation to be used for a region, got %q", got)
+	}
+	if got := Translate("Flags:"); got != "Flags:" {
+		t.Errorf("Expected untranslated messages to be returned as is, got %q", got)
+	}
+
+	AddTranslations("de_AT", map[string]string{"Usage:": "Benutzung:"})
+	if got := Translate("Usage:"); got != "Benutzung:" {
+		t.Errorf("Expected the region translation to be preferred, got %q", got)
+	}
+
+	SetLocale("ja")
+	AddTranslations("ja", map[string]string{"Usage:": "translations"})
+	AddMessageCatalog(testCatalog{"Usage:": "catalog"})
+	if got := Translate("Usage:"); got != "catalog" {
+		t.Errorf("Expected catalogs to take precedence over translations, got %q", got)
+	}
+}
+
+func TestLocalizedHelpAndErrors(t *testing.T) {
+	defer resetLocalization()
+	SetLocale("de")
+	AddTranslations("de", map[string]string{
+		"Usage:":                         "Verwendung:",
+		"Flags:":                         "Optionen:",
+		"help for %s":                    "Hilfe für %s",
+		"the child":                      "das Kind",
+		"Available Commands:":            "Verfügbare Befehle:",
+		"Error:":                         "Fehler:",
+		"accepts %d arg(s), received %d": "akzeptiert %d Argument(e), %d erhalten",
+	})
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Short: "the child", Args: ExactArgs(1), Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Verwendung:\n")
+	checkStringContains(t, output, "Verfügbare Befehle:\n")
+	checkStringContains(t, output, "child       das Kind")
+	checkStringContains(t, output, "Optionen:\n")
+	checkStringContains(t, output, "Hilfe für root")
+
+	output, err = executeCommand(rootCmd, "child")
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+	checkStringContains(t, output, "Fehler: akzeptiert 1 Argument(e), 0 erhalten")
+}
+
+func TestLocalizedFlagUsagesAndDefaultCommands(t *testing.T) {
+	defer resetLocalization()
+	SetLocale("de")
+	AddTranslations("de", map[string]string{
+		"the name to greet":       "der zu grüßende Name",
+		"Help about any command":  "Hilfe zu jedem Befehl",
+		"help for %s":             "Hilfe für %s",
+		"enable the verbose mode": "den ausführlichen Modus einschalten",
+		"Generate the autocompletion script for the specified shell": "Das Vervollständigungsskript für die angegebene Shell erzeugen",
+	})
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().String("name", "", "the name to greet")
+	rootCmd.PersistentFlags().Bool("verbose", false, "enable the verbose mode")
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--name string   der zu grüßende Name")
+	checkStringContains(t, output, "--verbose       den ausführlichen Modus einschalten")
+	checkStringContains(t, output, "Hilfe für root")
+	checkStringContains(t, output, "help        Hilfe zu jedem Befehl")
+	checkStringContains(t, output, "completion  Das Vervollständigungsskript für die angegebene Shell erzeugen")
+
+	output, err = executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--verbose   den ausführlichen Modus einschalten")
+}
@@ -0,0 +1,236 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	verboseFlagName  = "verbose"
+	quietFlagName    = "quiet"
+	logLevelFlagName = "log-level"
+)
+
+// LogLevel is the severity of a logged message. Messages are logged when their level
+// is at most the level chosen for the program.
+type LogLevel int
+
+// The log levels, from the most to the least severe.
+const (
+	LogLevelError LogLevel = iota + 1
+	LogLevelWarn
+	LogLevelInfo
+	LogLevelDebug
+)
+
+var logLevelNames = map[LogLevel]string{
+	LogLevelError: "error",
+	LogLevelWarn:  "warn",
+	LogLevelInfo:  "info",
+	LogLevelDebug: "debug",
+}
+
+// String returns the name of the level.
+func (l LogLevel) String() string {
+	if name, ok := logLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// ParseLogLevel returns the level named name: error, warn, info or debug.
+func ParseLogLevel(name string) (LogLevel, error) {
+	for level, levelName := range logLevelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf(Translate("invalid log level %q, must be one of: error, warn, info, debug"), name)
+}
+
+// LogBackend writes the messages logged by commands.
+type LogBackend interface {
+	Log(cmd *Command, level LogLevel, msg string)
+}
+
+// LogBackendFunc is a function used as a LogBackend.
+type LogBackendFunc func(cmd *Command, level LogLevel, msg string)
+
+// Log calls f(cmd, level, msg).
+func (f LogBackendFunc) Log(cmd *Command, level LogLevel, msg string) {
+	f(cmd, level, msg)
+}
+
+// defaultLogBackend writes messages to the error output of the command, prefixed by their level.
+var defaultLogBackend = LogBackendFunc(func(cmd *Command, level LogLevel, msg string) {
+	cmd.PrintErrf("%s: %s\n", level, msg)
+})
+
+// LoggingOptions are the options to control the logging of the program.
+type LoggingOptions struct {
+	// Enabled adds the '--verbose', '--quiet' and '--log-level' persistent flags to the root command
+	Enabled bool
+	// Level is the level used when it is not chosen with the flags. It defaults to LogLevelWarn.
+	Level LogLevel
+	// Backend writes the logged messages. It defaults to writing them to ErrOrStderr.
+	Backend LogBackend
+}
+
+// Logger logs messages for a command, at the level chosen for the program.
+type Logger struct {
+	cmd *Command
+}
+
+// Log returns the logger of c, configured by the LoggingOptions of the root command.
+func (c *Command) Log() *Logger {
+	return &Logger{cmd: c}
+}
+
+// Level returns the level of the messages logged.
+func (l *Logger) Level() LogLevel {
+	root := l.cmd.Root()
+	level := root.Logging.Level
+	if level == 0 {
+		level = LogLevelWarn
+	}
+	if !root.Logging.Enabled {
+		return level
+	}
+
+	flags := root.PersistentFlags()
+	if f := flags.Lookup(logLevelFlagName); f != nil && f.Changed {
+		if parsed, err := ParseLogLevel(f.Value.String()); err == nil {
+			return parsed
+		}
+	}
+	if quiet, err := flags.GetBool(quietFlagName); err == nil && quiet {
+		return LogLevelError
+	}
+	if verbose, err := flags.GetCount(verboseFlagName); err == nil && verbose > 0 {
+		level += LogLevel(verbose)
+		if level > LogLevelDebug {
+			level = LogLevelDebug
+		}
+	}
+	return level
+}
+
+// Enabled returns whether messages of level are logged.
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level <= l.Level()
+}
+
+// Logf logs a message of level, formatted according to format.
+func (l *Logger) Logf(level LogLevel, format string, a ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	backend := l.cmd.Root().Logging.Backend
+	if backend == nil {
+		backend = defaultLogBackend
+	}
+	backend.Log(l.cmd, level, fmt.Sprintf(format, a...))
+}
+
+// Errorf logs an error message.
+func (l *Logger) Errorf(format string, a ...interface{}) { l.Logf(LogLevelError, format, a...) }
+
+// Warnf logs a warning message.
+func (l *Logger) Warnf(format string, a ...interface{}) { l.Logf(LogLevelWarn, format, a...) }
+
+// Infof logs an informational message.
+func (l *Logger) Infof(format string, a ...interface{}) { l.Logf(LogLevelInfo, format, a...) }
+
+// Debugf logs a debugging message.
+func (l *Logger) Debugf(format string, a ...interface{}) { l.Logf(LogLevelDebug, format, a...) }
+
+// logLevelValue is the value of the '--log-level' flag.
+type logLevelValue string
+
+func (v *logLevelValue) String() string { return string(*v) }
+
+func (v *logLevelValue) Set(s string) error {
+	if _, err := ParseLogLevel(s); err != nil {
+		return err
+	}
+	*v = logLevelValue(strings.ToLower(s))
+	return nil
+}
+
+func (v *logLevelValue) Type() string { return "string" }
+
+// initLoggingFlags adds the '--verbose', '--quiet' and '--log-level' persistent flags
+// to the root command c if logging is enabled. The flags c already has are not replaced.
+func (c *Command) initLoggingFlags() {
+	if !c.Logging.Enabled {
+		return
+	}
+
+	flags := c.PersistentFlags()
+	if flags.Lookup(verboseFlagName) == nil {
+		usage := Translate("log more messages, repeat for even more")
+		if c.shorthandFreeInTree("v") {
+			flags.CountP(verboseFlagName, "v", usage)
+		} else {
+			flags.Count(verboseFlagName, usage)
+		}
+		_ = flags.SetAnnotation(verboseFlagName, FlagSetByCobraAnnotation, []string{"true"})
+	}
+	if flags.Lookup(quietFlagName) == nil {
+		usage := Translate("only log errors")
+		if c.shorthandFreeInTree("q") {
+			flags.BoolP(quietFlagName, "q", false, usage)
+		} else {
+			flags.Bool(quietFlagName, false, usage)
+		}
+		_ = flags.SetAnnotation(quietFlagName, FlagSetByCobraAnnotation, []string{"true"})
+	}
+	if flags.Lookup(logLevelFlagName) == nil {
+		level := logLevelValue("")
+		flags.Var(&level, logLevelFlagName, Translate("level of the logged messages, one of: error|warn|info|debug"))
+		_ = flags.SetAnnotation(logLevelFlagName, FlagSetByCobraAnnotation, []string{"true"})
+		_ = c.RegisterFlagCompletionFunc(logLevelFlagName, FixedCompletions([]string{"error", "warn", "info", "debug"}, ShellCompDirectiveNoFileComp))
+	}
+}
+
+// shorthandFreeInTree returns whether no command of the tree of c defines a flag
+// with the shorthand s, which a persistent flag of c would conflict with. -v is
+// also taken by the commands with a version, for their --version flag.
+func (c *Command) shorthandFreeInTree(s string) bool {
+	if c.Flags().ShorthandLookup(s) != nil || c.PersistentFlags().ShorthandLookup(s) != nil {
+		return false
+	}
+	if s == "v" && c.Version != "" {
+		return false
+	}
+	for _, sub := range c.commands {
+		if !sub.shorthandFreeInTree(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// compErrorln reports msg, an error in the completion of c, through the logger
+// of c when it logs debugging messages, or with CompErrorln otherwise.
+func (c *Command) compErrorln(msg string) {
+	if logger := c.Log(); logger.Enabled(LogLevelDebug) {
+		logger.Errorf("%s", msg)
+		return
+	}
+	CompErrorln(msg)
+}
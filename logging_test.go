@@ -0,0 +1,184 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func loggingTestCmd(log func(*Command)) *Command {
+	rootCmd := &Command{Use: "root", Run: emptyRun, Logging: LoggingOptions{Enabled: true}}
+	childCmd := &Command{
+		Use: "child",
+		Run: func(cmd *Command, _ []string) {
+			log(cmd)
+		},
+	}
+	rootCmd.AddCommand(childCmd)
+	return rootCmd
+}
+
+func logAllLevels(cmd *Command) {
+	cmd.Log().Errorf("e%d", 1)
+	cmd.Log().Warnf("w")
+	cmd.Log().Infof("i")
+	cmd.Log().Debugf("d")
+}
+
+func TestLogLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "Default", args: nil, expected: "error: e1\nwarn: w\n"},
+		{name: "Verbose", args: []string{"-v"}, expected: "error: e1\nwarn: w\ninfo: i\n"},
+		{name: "Quiet", args: []string{"--quiet"}, expected: "error: e1\n"},
+		{name: "Log level", args: []string{"--log-level", "INFO", "-q"}, expected: "error: e1\nwarn: w\ninfo: i\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := loggingTestCmd(logAllLevels)
+			output, err := executeCommand(rootCmd, append([]string{"child"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestLogDebugLevel(t *testing.T) {
+	rootCmd := loggingTestCmd(logAllLevels)
+	output, err := executeCommand(rootCmd, "child", "-vvv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Cobra logs its own decisions at the debug level.
+	checkStringContains(t, output, "debug: resolved command \"root child\", called as \"child\", with arguments []\n")
+	checkStringContains(t, output, "info: i\ndebug: d\n")
+}
+
+func TestLogLevelInvalid(t *testing.T) {
+	rootCmd := loggingTestCmd(logAllLevels)
+	_, err := executeCommand(rootCmd, "child", "--log-level", "loud")
+	if err == nil || !strings.Contains(err.Error(), `invalid log level "loud"`) {
+		t.Errorf("Expected an invalid log level error, got %v", err)
+	}
+}
+
+func TestLogBackend(t *testing.T) {
+	var logged []string
+	rootCmd := loggingTestCmd(func(cmd *Command) {
+		cmd.Log().Warnf("careful")
+	})
+	rootCmd.Logging.Level = LogLevelError
+	rootCmd.Logging.Backend = LogBackendFunc(func(cmd *Command, level LogLevel, msg string) {
+		logged = append(logged, cmd.Name()+" "+level.String()+" "+msg)
+	})
+
+	if _, err := executeCommand(rootCmd, "child", "-v"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(logged) != 1 || logged[0] != "child warn careful" {
+		t.Errorf("Unexpected messages: %q", logged)
+	}
+}
+
+func TestLoggingDisabled(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: func(cmd *Command, _ []string) { logAllLevels(cmd) }}
+	output, err := executeCommand(rootCmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "error: e1\nwarn: w\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+	if rootCmd.Flags().Lookup("verbose") != nil {
+		t.Error("Expected no logging flags")
+	}
+}
+
+func TestLoggingFlagsKeepVersionShorthand(t *testing.T) {
+	rootCmd := &Command{Use: "root", Version: "1.0", Run: emptyRun, Logging: LoggingOptions{Enabled: true}}
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "      --verbose count")
+	checkStringContains(t, output, "-v, --version ")
+
+	rootCmd = &Command{Use: "root", Version: "1.0", Run: emptyRun, Logging: LoggingOptions{Enabled: true}}
+	output, err = executeCommand(rootCmd, "-v")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "root version 1.0")
+}
+
+func TestLoggingFlagsVerboseShorthand(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun, Logging: LoggingOptions{Enabled: true}}
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "-v, --verbose count")
+
+	rootCmd = &Command{Use: "root", Run: emptyRun, Logging: LoggingOptions{Enabled: true}}
+	rootCmd.Flags().BoolP("verify", "v", false, "verify")
+	output, err = executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "      --verbose count")
+	checkStringContains(t, output, "-v, --verify ")
+}
+
+func TestLoggingFlagsShorthandsOfSubcommands(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun, Logging: LoggingOptions{Enabled: true}}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().BoolP("quick", "q", false, "quick")
+	versionedCmd := &Command{Use: "versioned", Version: "1.0", Run: emptyRun}
+	rootCmd.AddCommand(childCmd, versionedCmd)
+
+	output, err := executeCommand(rootCmd, "child", "-q", "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "-q, --quick")
+	checkStringContains(t, output, "      --quiet")
+	checkStringContains(t, output, "      --verbose count")
+
+	output, err = executeCommand(rootCmd, "versioned", "-v")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "versioned version 1.0")
+}
+
+func TestCompletionErrorThroughLogger(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun, Logging: LoggingOptions{Enabled: true, Level: LogLevelDebug}}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	output, err := executeCommand(rootCmd, ShellCompRequestCmd, "child", "--unknown", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "error: Error while parsing flags from args [--unknown]: unknown flag: --unknown\n")
+}
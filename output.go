@@ -0,0 +1,373 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const outputFlagName = "output"
+
+// The output formats provided by Cobra.
+const (
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputTable    = "table"
+	OutputTemplate = "template"
+)
+
+// OutputFormatter renders data, as returned by a command, in an output format.
+// arg is the text following "=" in the value of the --output flag, such as the
+// template of --output 'template={{.Name}}'.
+type OutputFormatter func(w io.Writer, data interface{}, arg string) error
+
+// OutputOptions are the options to control the output of the data of a command.
+type OutputOptions struct {
+	// Enabled adds the '--output' flag choosing the format the data of the command is rendered in
+	Enabled bool
+	// Formats are the formats allowed for the command. All registered formats are allowed when empty.
+	Formats []string
+	// Default is the format used when the '--output' flag is not given. It defaults to "table".
+	Default string
+}
+
+var (
+	outputFormatters = map[string]OutputFormatter{
+		OutputJSON:     formatJSON,
+		OutputYAML:     formatYAML,
+		OutputTable:    formatTable,
+		OutputTemplate: formatTemplate,
+	}
+	outputFormattersMutex sync.RWMutex
+)
+
+// RegisterOutputFormat registers the formatter of the output format name, which may
+// then be chosen with the '--output' flag. It replaces any formatter registered for name.
+func RegisterOutputFormat(name string, f OutputFormatter) {
+	outputFormattersMutex.Lock()
+	defer outputFormattersMutex.Unlock()
+	outputFormatters[name] = f
+}
+
+// outputFormats returns the output formats allowed for c.
+func (c *Command) outputFormats() []string {
+	if len(c.Output.Formats) > 0 {
+		return c.Output.Formats
+	}
+	outputFormattersMutex.RLock()
+	defer outputFormattersMutex.RUnlock()
+	formats := make([]string, 0, len(outputFormatters))
+	for name := range outputFormatters {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// initOutputFlag adds the '--output' flag to c if its output is enabled.
+// If c already has such a flag, it will do nothing.
+func (c *Command) initOutputFlag() {
+	if !c.Output.Enabled {
+		return
+	}
+
+	c.mergePersistentFlags()
+	if c.Flags().Lookup(outputFlagName) != nil {
+		return
+	}
+	formats := c.outputFormats()
+	usage := fmt.Sprintf(Translate("output format, one of: %s"), strings.Join(formats, "|"))
+	if c.Flags().ShorthandLookup("o") == nil {
+		c.Flags().StringP(outputFlagName, "o", c.outputDefault(), usage)
+	} else {
+		c.Flags().String(outputFlagName, c.outputDefault(), usage)
+	}
+	_ = c.Flags().SetAnnotation(outputFlagName, FlagSetByCobraAnnotation, []string{"true"})
+
+	_ = c.RegisterFlagCompletionFunc(outputFlagName, func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+		var completions []string
+		directive := ShellCompDirectiveNoFileComp
+		for _, format := range formats {
+			if format == OutputTemplate {
+				// The template follows the format name.
+				format += "="
+				if toComplete != "" && strings.HasPrefix(format, toComplete) {
+					directive |= ShellCompDirectiveNoSpace
+				}
+			}
+			completions = append(completions, format)
+		}
+		return completions, directive
+	})
+}
+
+// outputDefault returns the format used when the '--output' flag is not given.
+func (c *Command) outputDefault() string {
+	if c.Output.Default != "" {
+		return c.Output.Default
+	}
+	return OutputTable
+}
+
+// outputFormat returns the output format chosen for c and its argument.
+func (c *Command) outputFormat() (format, arg string) {
+	format = c.outputDefault()
+	if f := c.Flags().Lookup(outputFlagName); f != nil && c.Output.Enabled {
+		format = f.Value.String()
+	}
+	if eq := strings.Index(format, "="); eq >= 0 {
+		return format[:eq], format[eq+1:]
+	}
+	return format, ""
+}
+
+// validateOutputFormat checks that the output format chosen for c is allowed and registered.
+func (c *Command) validateOutputFormat() error {
+	if !c.Output.Enabled {
+		return nil
+	}
+	format, _ := c.outputFormat()
+	formats := c.outputFormats()
+	if !stringInSlice(format, formats) {
+		return &CommandError{
+			Kind:  ErrorKindInvalidFlagValue,
+			Flags: []string{outputFlagName},
+			Err:   fmt.Errorf(Translate("invalid output format %q, must be one of: %s"), format, strings.Join(formats, ", ")),
+		}
+	}
+	outputFormattersMutex.RLock()
+	_, ok := outputFormatters[format]
+	outputFormattersMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf(Translate("output format %q is not registered"), format)
+	}
+	return nil
+}
+
+// PrintOutput renders data to the output of the command, in the format chosen
+// with the '--output' flag. It is called with the data returned by RunOutput.
+func (c *Command) PrintOutput(data interface{}) error {
+	if err := c.validateOutputFormat(); err != nil {
+		return err
+	}
+	format, arg := c.outputFormat()
+	outputFormattersMutex.RLock()
+	formatter, ok := outputFormatters[format]
+	outputFormattersMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf(Translate("output format %q is not registered"), format)
+	}
+	return formatter(c.OutOrStdout(), data, arg)
+}
+
+func formatJSON(w io.Writer, data interface{}, _ string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+func formatYAML(w io.Writer, data interface{}, _ string) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(data); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func formatTemplate(w io.Writer, data interface{}, text string) error {
+	if text == "" {
+		return fmt.Errorf(Translate("output format %q needs a template, such as %s"), OutputTemplate, "template='{{.}}'")
+	}
+	t, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return err
+	}
+	out := sb.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// Tabular is implemented by data rendering itself as a table.
+type Tabular interface {
+	TableHeader() []string
+	TableRows() [][]string
+}
+
+// formatTable renders data as a table aligned in columns. The rows are the elements
+// of a slice, or data itself, and the columns are the exported fields of structs,
+// named by their json tag if any, or the keys of maps.
+func formatTable(w io.Writer, data interface{}, _ string) error {
+	header, rows := tableOf(data)
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	if len(header) > 0 {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tableOf returns the header and rows of the table rendering data.
+func tableOf(data interface{}) ([]string, [][]string) {
+	if t, ok := data.(Tabular); ok {
+		return t.TableHeader(), t.TableRows()
+	}
+
+	v := indirectValue(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return nil, nil
+	}
+	var elems []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elems = append(elems, indirectValue(v.Index(i)))
+		}
+	case reflect.Map:
+		// A single map is rendered as its keys and values.
+		keys := sortedMapKeys(v)
+		rows := make([][]string, len(keys))
+		for i, key := range keys {
+			rows[i] = []string{fmt.Sprint(key.Interface()), cellText(v.MapIndex(key))}
+		}
+		return []string{"KEY", "VALUE"}, rows
+	default:
+		elems = []reflect.Value{v}
+	}
+	if len(elems) == 0 {
+		return nil, nil
+	}
+
+	switch elems[0].Kind() {
+	case reflect.Struct:
+		t := elems[0].Type()
+		var header []string
+		var fields []int
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			header = append(header, strings.ToUpper(name))
+			fields = append(fields, i)
+		}
+		rows := make([][]string, 0, len(elems))
+		for _, elem := range elems {
+			row := make([]string, len(fields))
+			if elem.IsValid() && elem.Type() == t {
+				for j, i := range fields {
+					row[j] = cellText(elem.Field(i))
+				}
+			}
+			rows = append(rows, row)
+		}
+		return header, rows
+	case reflect.Map:
+		var keys []string
+		for _, elem := range elems {
+			if !elem.IsValid() || elem.Kind() != reflect.Map {
+				continue
+			}
+			for _, key := range elem.MapKeys() {
+				if k := fmt.Sprint(key.Interface()); !stringInSlice(k, keys) {
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+		header := make([]string, len(keys))
+		for i, key := range keys {
+			header[i] = strings.ToUpper(key)
+		}
+		rows := make([][]string, 0, len(elems))
+		for _, elem := range elems {
+			row := make([]string, len(keys))
+			if !elem.IsValid() || elem.Kind() != reflect.Map {
+				rows = append(rows, row)
+				continue
+			}
+			for _, key := range elem.MapKeys() {
+				k := fmt.Sprint(key.Interface())
+				for i := range keys {
+					if keys[i] == k {
+						row[i] = cellText(elem.MapIndex(key))
+					}
+				}
+			}
+			rows = append(rows, row)
+		}
+		return header, rows
+	default:
+		rows := make([][]string, 0, len(elems))
+		for _, elem := range elems {
+			rows = append(rows, []string{cellText(elem)})
+		}
+		return []string{"VALUE"}, rows
+	}
+}
+
+// indirectValue dereferences the pointers and interfaces of v.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// cellText returns the text of a table cell holding v.
+func cellText(v reflect.Value) string {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// sortedMapKeys returns the keys of the map v, sorted by their text.
+func sortedMapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,200 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type outputTestItem struct {
+	Name    string `json:"name" yaml:"name"`
+	Size    int    `json:"size" yaml:"size"`
+	Private string `json:"-" yaml:"-"`
+}
+
+func outputTestCmd() *Command {
+	return &Command{
+		Use:    "list",
+		Output: OutputOptions{Enabled: true},
+		RunOutput: func(*Command, []string) (interface{}, error) {
+			return []outputTestItem{{Name: "alpha", Size: 1}, {Name: "b", Size: 200}}, nil
+		},
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Default table",
+			args:     nil,
+			expected: "NAME    SIZE\nalpha   1\nb       200\n",
+		},
+		{
+			name:     "JSON",
+			args:     []string{"-o", "json"},
+			expected: "[\n  {\n    \"name\": \"alpha\",\n    \"size\": 1\n  },\n  {\n    \"name\": \"b\",\n    \"size\": 200\n  }\n]\n",
+		},
+		{
+			name:     "YAML",
+			args:     []string{"--output", "yaml"},
+			expected: "- name: alpha\n  size: 1\n- name: b\n  size: 200\n",
+		},
+		{
+			name:     "Template",
+			args:     []string{"--output=template={{range .}}{{.Name}} {{end}}"},
+			expected: "alpha b \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(outputTestCmd(), tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestOutputInvalidFormat(t *testing.T) {
+	c := outputTestCmd()
+	c.Output.Formats = []string{OutputJSON, OutputTable}
+
+	_, err := executeCommand(c, "-o", "yaml")
+	if err == nil || err.Error() != `invalid output format "yaml", must be one of: json, table` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestOutputDefaultFormat(t *testing.T) {
+	c := outputTestCmd()
+	c.Output.Default = OutputJSON
+
+	output, err := executeCommand(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "\"name\": \"alpha\"")
+}
+
+func TestOutputCompletion(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	c := outputTestCmd()
+	c.Output.Formats = []string{OutputJSON, OutputTemplate}
+	rootCmd.AddCommand(c)
+
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "list", "--output", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := strings.Join([]string{"json", "template=", ":4", "Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+	if output != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, output)
+	}
+}
+
+func TestRegisterOutputFormat(t *testing.T) {
+	RegisterOutputFormat("names", func(w io.Writer, data interface{}, arg string) error {
+		for _, item := range data.([]outputTestItem) {
+			fmt.Fprintf(w, "%s%s", item.Name, arg)
+		}
+		return nil
+	})
+	defer func() {
+		outputFormattersMutex.Lock()
+		delete(outputFormatters, "names")
+		outputFormattersMutex.Unlock()
+	}()
+
+	output, err := executeCommand(outputTestCmd(), "-o", "names=;")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "alpha;b;" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+}
+
+type outputTestTable struct{}
+
+func (outputTestTable) TableHeader() []string { return []string{"A", "B"} }
+func (outputTestTable) TableRows() [][]string { return [][]string{{"1", "2"}} }
+
+func TestFormatTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{
+			name:     "Single struct",
+			data:     &outputTestItem{Name: "alpha", Size: 1},
+			expected: "NAME    SIZE\nalpha   1\n",
+		},
+		{
+			name:     "Single map",
+			data:     map[string]int{"b": 2, "a": 1},
+			expected: "KEY   VALUE\na     1\nb     2\n",
+		},
+		{
+			name:     "Slice of maps",
+			data:     []map[string]string{{"x": "1"}, {"y": "2"}},
+			expected: "X   Y\n1   \n    2\n",
+		},
+		{
+			name:     "Slice of maps and other values",
+			data:     []interface{}{map[string]int{"a": 1}, nil, "text", map[string]int{"b": 2}},
+			expected: "A   B\n1   \n    \n    \n    2\n",
+		},
+		{
+			name:     "Slice of scalars",
+			data:     []string{"one", "two"},
+			expected: "VALUE\none\ntwo\n",
+		},
+		{
+			name:     "Tabular",
+			data:     outputTestTable{},
+			expected: "A   B\n1   2\n",
+		},
+		{
+			name:     "Nil",
+			data:     nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := formatTable(buf, tt.data, ""); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, buf.String())
+			}
+		})
+	}
+}
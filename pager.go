@@ -0,0 +1,151 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPager     = "less -FRX"
+	noPagerFlagName  = "no-pager"
+	noPagerFlagUsage = "do not pipe help output into a pager"
+
+	// The below values should not be changed: programs will be using them explicitly
+	// in their user documentation, and users will be using them explicitly.
+	configEnvVarSuffixPager = "PAGER"
+	pagerDisable            = "0"
+)
+
+// PagerOptions are the options to control paging of help output
+type PagerOptions struct {
+	// Enabled pipes help output that does not fit in the terminal into a pager
+	Enabled bool
+	// Command is the pager used when the user did not choose one through the
+	// <PROGRAM>_PAGER or PAGER environment variables. It defaults to "less -FRX".
+	Command string
+	// DisableNoPagerFlag prevents Cobra from creating the '--no-pager' flag
+	DisableNoPagerFlag bool
+}
+
+// initNoPagerFlag adds the '--no-pager' persistent flag to the root command c
+// if paging is enabled. If c already has such a flag, it will do nothing.
+func (c *Command) initNoPagerFlag() {
+	if !c.PagerOptions.Enabled || c.PagerOptions.DisableNoPagerFlag {
+		return
+	}
+	if c.PersistentFlags().Lookup(noPagerFlagName) == nil {
+		c.PersistentFlags().Bool(noPagerFlagName, false, Translate(noPagerFlagUsage))
+		_ = c.PersistentFlags().SetAnnotation(noPagerFlagName, FlagSetByCobraAnnotation, []string{"true"})
+	}
+}
+
+// helpPager returns the pager help output should be piped into,
+// or an empty string if it should not be paged.
+// The pager is taken from the <PROGRAM>_PAGER environment variable, where <PROGRAM>
+// is the name of the root command in upper case, then from COBRA_PAGER and PAGER.
+// Setting <PROGRAM>_PAGER or COBRA_PAGER to "0" disables paging, as does the
+// '--no-pager' flag.
+func (c *Command) helpPager() string {
+	root := c.Root()
+	if !root.PagerOptions.Enabled {
+		return ""
+	}
+	if noPager := root.PersistentFlags().Lookup(noPagerFlagName); noPager != nil {
+		if v, err := strconv.ParseBool(noPager.Value.String()); err == nil && v {
+			return ""
+		}
+	}
+
+	pager := getEnvConfig(c, configEnvVarSuffixPager)
+	if pager == pagerDisable {
+		return ""
+	}
+	if pager == "" {
+		pager = os.Getenv("PAGER")
+	}
+	if pager == "" {
+		pager = root.PagerOptions.Command
+	}
+	if pager == "" {
+		pager = defaultPager
+	}
+	return pager
+}
+
+// pageHelp calls help, which writes the help of c to its output. If the output
+// is a terminal and the help does not fit in it, the help is piped into the pager.
+// The help is written directly if the pager cannot be started.
+func (c *Command) pageHelp(help func()) {
+	out := c.OutOrStdout()
+	streams := c.Streams()
+	pager := c.helpPager()
+	if pager == "" || !streams.IsOutTerminal() {
+		help()
+		return
+	}
+
+	// Render into a buffer while still detecting the terminal on the real output
+	tmpOutput := c.outWriter
+	tmpRender := c.renderOut
+	buf := new(bytes.Buffer)
+	c.outWriter = buf
+	c.renderOut = out
+	help()
+	c.outWriter = tmpOutput
+	c.renderOut = tmpRender
+
+	if !fitsTerminal(streams.Height(), buf.Bytes()) {
+		if err := runPager(pager, buf.Bytes(), out, c.ErrOrStderr()); err == nil {
+			return
+		}
+	}
+	_, _ = out.Write(buf.Bytes())
+}
+
+// fitsTerminal reports whether content fits in a terminal of the given height.
+// If the height is unknown, content is assumed not to fit.
+func fitsTerminal(height int, content []byte) bool {
+	if height <= 0 {
+		return false
+	}
+	return bytes.Count(content, []byte("\n")) < height
+}
+
+// runPager pipes content into the pager, which writes to out and errOut.
+// An error is returned only if the pager could not be started, in which
+// case nothing has been written.
+func runPager(pager string, content []byte, out, errOut io.Writer) error {
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		return fmt.Errorf("empty pager command")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = out
+	cmd.Stderr = errOut
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// The pager exiting early, e.g. when the user quits it, is not an error
+	_ = cmd.Wait()
+	return nil
+}
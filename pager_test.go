@@ -0,0 +1,162 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestNoPagerFlagOnlyAddedWhenEnabled(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "--no-pager")
+
+	rootCmd = &Command{Use: "root", Run: emptyRun, PagerOptions: PagerOptions{Enabled: true}}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+	output, err = executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "--no-pager")
+
+	rootCmd = &Command{Use: "root", Run: emptyRun, PagerOptions: PagerOptions{Enabled: true, DisableNoPagerFlag: true}}
+	output, err = executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "--no-pager")
+}
+
+func TestHelpNotPagedWithoutTerminal(t *testing.T) {
+	rootCmd := &Command{Use: "root", Long: "root help", Run: emptyRun, PagerOptions: PagerOptions{Enabled: true, Command: "false"}}
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "root help")
+}
+
+func TestHelpPager(t *testing.T) {
+	os.Unsetenv("PAGER")
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	if pager := rootCmd.helpPager(); pager != "" {
+		t.Errorf("Expected no pager when paging is disabled, got %q", pager)
+	}
+
+	rootCmd.PagerOptions.Enabled = true
+	if pager := rootCmd.helpPager(); pager != defaultPager {
+		t.Errorf("Expected pager %q, got %q", defaultPager, pager)
+	}
+
+	rootCmd.PagerOptions.Command = "more"
+	if pager := rootCmd.helpPager(); pager != "more" {
+		t.Errorf("Expected pager %q, got %q", "more", pager)
+	}
+
+	os.Setenv("PAGER", "most")
+	defer os.Unsetenv("PAGER")
+	if pager := rootCmd.helpPager(); pager != "most" {
+		t.Errorf("Expected pager %q, got %q", "most", pager)
+	}
+
+	os.Setenv("ROOT_PAGER", "pg")
+	defer os.Unsetenv("ROOT_PAGER")
+	if pager := rootCmd.helpPager(); pager != "pg" {
+		t.Errorf("Expected pager %q, got %q", "pg", pager)
+	}
+
+	os.Setenv("ROOT_PAGER", pagerDisable)
+	if pager := rootCmd.helpPager(); pager != "" {
+		t.Errorf("Expected paging to be disabled by the environment, got %q", pager)
+	}
+	os.Unsetenv("ROOT_PAGER")
+
+	rootCmd.initNoPagerFlag()
+	if err := rootCmd.PersistentFlags().Set(noPagerFlagName, "true"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pager := rootCmd.helpPager(); pager != "" {
+		t.Errorf("Expected paging to be disabled by --%s, got %q", noPagerFlagName, pager)
+	}
+}
+
+func TestRunPager(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+
+	out := new(bytes.Buffer)
+	if err := runPager("cat", []byte("paged help\n"), out, out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := out.String(); got != "paged help\n" {
+		t.Errorf("Expected %q, got %q", "paged help\n", got)
+	}
+
+	out.Reset()
+	if err := runPager("cobra-missing-pager -R", []byte("help"), out, out); err == nil {
+		t.Errorf("Expected an error for a missing pager")
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got %q", out.String())
+	}
+}
+
+func TestFitsTerminal(t *testing.T) {
+	if !fitsTerminal(3, []byte("one\ntwo\n")) {
+		t.Errorf("Expected two lines to fit in three")
+	}
+	if fitsTerminal(3, []byte("one\ntwo\nthree\nfour\n")) {
+		t.Errorf("Expected four lines not to fit in three")
+	}
+	if fitsTerminal(0, []byte("one\n")) {
+		t.Errorf("Expected content not to fit in a terminal of unknown height")
+	}
+}
+
+func TestHelpPagedOnTerminal(t *testing.T) {
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr is not available")
+	}
+	if pager, ok := os.LookupEnv("PAGER"); ok {
+		os.Unsetenv("PAGER")
+		defer os.Setenv("PAGER", pager)
+	}
+
+	rootCmd := &Command{Use: "root", Long: "root help", Run: emptyRun, PagerOptions: PagerOptions{Enabled: true, Command: "tr a-z A-Z"}}
+	rootCmd.SetStreams(&Streams{Terminal: &TerminalInfo{Out: true, Height: 2}})
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "ROOT HELP")
+
+	rootCmd.SetStreams(&Streams{Terminal: &TerminalInfo{Out: true, Height: 100}})
+	output, err = executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "root help")
+}
@@ -0,0 +1,258 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// sensitiveFlag is the annotation marking a flag whose value is not echoed when prompted for.
+const sensitiveFlag = "cobra_annotation_sensitive"
+
+// MarkFlagSensitive instructs the prompts for the missing named flag to mask its value.
+func (c *Command) MarkFlagSensitive(name string) error {
+	return MarkFlagSensitive(c.Flags(), name)
+}
+
+// MarkPersistentFlagSensitive instructs the prompts for the missing named persistent flag
+// to mask its value.
+func (c *Command) MarkPersistentFlagSensitive(name string) error {
+	return MarkFlagSensitive(c.PersistentFlags(), name)
+}
+
+// MarkFlagSensitive instructs the prompts for the missing named flag to mask its value.
+func MarkFlagSensitive(flags *pflag.FlagSet, name string) error {
+	return flags.SetAnnotation(name, sensitiveFlag, []string{"true"})
+}
+
+// prompter reads the answers to prompts from the input of a command.
+type prompter struct {
+	cmd *Command
+	in  io.Reader
+}
+
+// newPrompter returns a prompter for the missing input of c, or nil if
+// PromptMissing is not set on c or its parents, or the input is not a terminal.
+func (c *Command) newPrompter() *prompter {
+	enabled := false
+	for p := c; p != nil; p = p.parent {
+		enabled = enabled || p.PromptMissing
+	}
+	if !enabled || c.DisableFlagParsing || !c.Streams().Interactive() {
+		return nil
+	}
+	return &prompter{cmd: c, in: c.InOrStdin()}
+}
+
+// promptMissingArgs prompts for the positional arguments missing from args, for
+// as long as they do not satisfy the Args of the command and the Use line of the
+// command names the next required argument, such as "delete <name>".
+func (p *prompter) promptMissingArgs(args []string) []string {
+	names := requiredArgNames(p.cmd.Use)
+	for len(args) < len(names) && p.cmd.ValidateArgs(args) != nil {
+		choices := argChoices(p.cmd, args)
+		value, err := p.ask(names[len(args)], "", choices, false)
+		if err != nil {
+			break
+		}
+		args = append(args, value)
+	}
+	return args
+}
+
+// promptMissingFlags prompts for the required flags which are not set.
+func (p *prompter) promptMissingFlags() {
+	p.cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if required, found := f.Annotations[BashCompOneRequiredFlag]; !found || required[0] != "true" || f.Changed {
+			return
+		}
+		label := "--" + f.Name
+		if f.Usage != "" {
+			label = fmt.Sprintf("%s (--%s)", f.Usage, f.Name)
+		}
+		_, sensitive := f.Annotations[sensitiveFlag]
+		def := ""
+		if !sensitive && !isZeroFlagDefault(f) {
+			def = f.DefValue
+		}
+		choices := flagChoices(p.cmd, f)
+		for {
+			value, err := p.ask(label, def, choices, sensitive)
+			if err != nil {
+				return
+			}
+			if err := p.cmd.Flags().Set(f.Name, value); err != nil {
+				fmt.Fprintln(p.cmd.ErrOrStderr(), err)
+				continue
+			}
+			return
+		}
+	})
+}
+
+// ask prompts for a value described by label, showing def, the value taken for an
+// empty answer. Without a default, the value is required and an empty answer is
+// asked again. When choices are given, they are listed and the answer may be the
+// number of a choice. A sensitive value is not echoed.
+func (p *prompter) ask(label, def string, choices []string, sensitive bool) (string, error) {
+	out := p.cmd.ErrOrStderr()
+	if len(choices) > 0 {
+		fmt.Fprintf(out, "%s:\n", label)
+		for i, choice := range choices {
+			fmt.Fprintf(out, "  %d) %s\n", i+1, choice)
+		}
+		label = Translate("Choice")
+	}
+	if def != "" {
+		label = fmt.Sprintf("%s [%s]", label, def)
+	}
+
+	for {
+		fmt.Fprintf(out, "%s: ", label)
+		answer, err := p.readLine(sensitive)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			if def != "" {
+				return def, nil
+			}
+			continue
+		}
+		if len(choices) == 0 {
+			return answer, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		if stringInSlice(answer, choices) {
+			return answer, nil
+		}
+		fmt.Fprintf(out, Translate("Invalid choice %q")+"\n", answer)
+	}
+}
+
+// readLine reads an answer from the input, without echoing it if sensitive.
+func (p *prompter) readLine(sensitive bool) (string, error) {
+	if sensitive {
+		f, ok := terminalFile(p.cmd.InOrStdin())
+		if !ok {
+			return "", errors.New("cannot mask the input")
+		}
+		restore, err := disableEcho(f)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			restore()
+			// The newline typed by the user was not echoed.
+			fmt.Fprintln(p.cmd.ErrOrStderr())
+		}()
+	}
+
+	// The input is read a byte at a time, so that nothing past the answer is
+	// consumed and the command can still read the rest of its input.
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := p.in.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+// isZeroFlagDefault reports whether the default value of f is the zero value of
+// its type, such as "", "0" or "false", which is not offered as an answer.
+func isZeroFlagDefault(f *pflag.Flag) bool {
+	switch f.DefValue {
+	case "", "0", "false", "[]", "map[]", "0s", "<nil>":
+		return true
+	}
+	return false
+}
+
+// requiredArgNames returns the names of the required positional arguments in the
+// Use line use, such as "name" for "delete <name> [flags]".
+func requiredArgNames(use string) []string {
+	var names []string
+	fields := strings.Fields(use)
+	for i, field := range fields {
+		if i == 0 || strings.HasPrefix(field, "[") {
+			continue
+		}
+		name := strings.TrimSuffix(field, "...")
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">")
+		names = append(names, name)
+	}
+	return names
+}
+
+// argChoices returns the values the next positional argument of c may take,
+// as given by its ValidArgs or ValidArgsFunction.
+func argChoices(c *Command, args []string) []string {
+	if len(c.ValidArgs) > 0 {
+		return completionValues(c.ValidArgs)
+	}
+	if c.ValidArgsFunction != nil {
+		completions, directive := c.ValidArgsFunction(c, args, "")
+		if directive&ShellCompDirectiveError == 0 {
+			return completionValues(completions)
+		}
+	}
+	return nil
+}
+
+// flagChoices returns the values f may take, as given by its completion function.
+func flagChoices(c *Command, f *pflag.Flag) []string {
+	flagCompletionMutex.RLock()
+	completionFn, ok := flagCompletionFunctions[f]
+	flagCompletionMutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	completions, directive := completionFn(c, c.Flags().Args(), "")
+	if directive&ShellCompDirectiveError != 0 {
+		return nil
+	}
+	return completionValues(completions)
+}
+
+// completionValues returns completions without their descriptions.
+func completionValues(completions []string) []string {
+	var values []string
+	for _, completion := range completions {
+		if value := strings.SplitN(completion, "\t", 2)[0]; value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
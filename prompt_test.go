@@ -0,0 +1,209 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// terminalInput returns streams reading input from a terminal.
+func terminalInput(input string) *Streams {
+	return &Streams{In: strings.NewReader(input), Terminal: &TerminalInfo{In: true}}
+}
+
+func TestPromptMissingFlag(t *testing.T) {
+	var name string
+	c := &Command{Use: "c", PromptMissing: true, Run: emptyRun}
+	c.Flags().StringVar(&name, "name", "", "the name")
+	assertNoErr(t, c.MarkFlagRequired("name"))
+	c.SetStreams(terminalInput("alice\n"))
+	errOut := new(bytes.Buffer)
+	c.SetErr(errOut)
+
+	if err := c.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name != "alice" || !c.Flags().Changed("name") {
+		t.Errorf("Expected the prompted value to be set, got %q", name)
+	}
+	if got := errOut.String(); got != "the name (--name): " {
+		t.Errorf("Unexpected prompt: %q", got)
+	}
+}
+
+func TestPromptMissingFlagDefaultAndChoices(t *testing.T) {
+	var format string
+	rootCmd := &Command{Use: "root", PromptMissing: true}
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().StringVar(&format, "format", "json", "output format")
+	assertNoErr(t, c.MarkFlagRequired("format"))
+	assertNoErr(t, c.RegisterFlagCompletionFunc("format", FixedCompletions([]string{"json\tJSON", "yaml\tYAML"}, ShellCompDirectiveNoFileComp)))
+	rootCmd.AddCommand(c)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "\n", expected: "json"},
+		{input: "2\n", expected: "yaml"},
+		{input: "xml\nyaml\n", expected: "yaml"},
+	}
+	for _, tt := range tests {
+		format = ""
+		c.Flags().Lookup("format").Changed = false
+		rootCmd.SetStreams(terminalInput(tt.input))
+		output, err := executeCommand(rootCmd, "c")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if format != tt.expected {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.expected, format)
+		}
+		checkStringContains(t, output, "output format (--format):\n  1) json\n  2) yaml\nChoice [json]: ")
+	}
+}
+
+func TestPromptMissingFlagEmptyAnswer(t *testing.T) {
+	var force bool
+	c := &Command{Use: "c", PromptMissing: true, Run: emptyRun}
+	c.Flags().BoolVar(&force, "force", false, "force the deletion")
+	assertNoErr(t, c.MarkFlagRequired("force"))
+
+	// An empty answer does not accept the default value of a required flag.
+	c.SetStreams(terminalInput("\n"))
+	output, err := executeCommand(c)
+	if err == nil || err.Error() != `required flag(s) "force" not set` {
+		t.Errorf("Expected the required flag error, got %v", err)
+	}
+	checkStringContains(t, output, "force the deletion (--force): force the deletion (--force): ")
+	checkStringOmits(t, output, "[false]")
+
+	c.Flags().Lookup("force").Changed = false
+	c.SetStreams(terminalInput("\ntrue\n"))
+	if _, err := executeCommand(c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !force {
+		t.Errorf("Expected the value given after the empty answer to be set")
+	}
+}
+
+func TestPromptMissingFlagDefault(t *testing.T) {
+	var region string
+	c := &Command{Use: "c", PromptMissing: true, Run: emptyRun}
+	c.Flags().StringVar(&region, "region", "eu-west", "the region")
+	assertNoErr(t, c.MarkFlagRequired("region"))
+	c.SetStreams(terminalInput("\n"))
+
+	output, err := executeCommand(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if region != "eu-west" || !c.Flags().Changed("region") {
+		t.Errorf("Expected the default value to be taken, got %q", region)
+	}
+	checkStringContains(t, output, "the region (--region) [eu-west]: ")
+}
+
+func TestPromptMissingKeepsRemainingInput(t *testing.T) {
+	var name, rest string
+	c := &Command{
+		Use:           "c",
+		PromptMissing: true,
+		RunE: func(c *Command, _ []string) error {
+			data, err := ioutil.ReadAll(c.InOrStdin())
+			rest = string(data)
+			return err
+		},
+	}
+	c.Flags().StringVar(&name, "name", "", "the name")
+	assertNoErr(t, c.MarkFlagRequired("name"))
+	c.SetStreams(terminalInput("alice\nthe rest\nof the input\n"))
+
+	if _, err := executeCommand(c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name != "alice" {
+		t.Errorf("Expected the prompted value to be set, got %q", name)
+	}
+	if rest != "the rest\nof the input\n" {
+		t.Errorf("Expected the input after the answer to be left to the command, got %q", rest)
+	}
+}
+
+func TestPromptMissingArgs(t *testing.T) {
+	var got []string
+	c := &Command{
+		Use:           "delete <kind> <name>",
+		Args:          ExactArgs(2),
+		PromptMissing: true,
+		ValidArgsFunction: func(_ *Command, args []string, _ string) ([]string, ShellCompDirective) {
+			if len(args) == 0 {
+				return []string{"user", "group"}, ShellCompDirectiveNoFileComp
+			}
+			return nil, ShellCompDirectiveNoFileComp
+		},
+		Run: func(_ *Command, args []string) {
+			got = args
+		},
+	}
+	c.SetStreams(terminalInput("1\n\nbob\n"))
+
+	output, err := executeCommand(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"user", "bob"}) {
+		t.Errorf("Expected the prompted argument, got %q", got)
+	}
+	checkStringContains(t, output, "kind:\n  1) user\n  2) group\nChoice: name: name: ")
+}
+
+func TestPromptMissingNotInteractive(t *testing.T) {
+	var name string
+	c := &Command{Use: "c", PromptMissing: true, Run: emptyRun}
+	c.Flags().StringVar(&name, "name", "", "the name")
+	assertNoErr(t, c.MarkFlagRequired("name"))
+	c.SetIn(strings.NewReader("alice\n"))
+
+	_, err := executeCommand(c)
+	if err == nil || err.Error() != `required flag(s) "name" not set` {
+		t.Errorf("Expected the required flag error, got %v", err)
+	}
+}
+
+func TestPromptMissingEndOfInput(t *testing.T) {
+	c := &Command{Use: "c", PromptMissing: true, Run: emptyRun}
+	c.Flags().String("name", "", "the name")
+	assertNoErr(t, c.MarkFlagRequired("name"))
+	c.SetStreams(terminalInput(""))
+
+	_, err := executeCommand(c)
+	if err == nil || err.Error() != `required flag(s) "name" not set` {
+		t.Errorf("Expected the required flag error, got %v", err)
+	}
+}
+
+func TestRequiredArgNames(t *testing.T) {
+	got := requiredArgNames("cp <src>... DEST [flags] [extra]")
+	expected := []string{"src", "DEST"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecRegistry holds the Go functions the commands loaded by LoadSpec are bound to,
+// by handler name: the handler of the command in the spec, or else its path, such
+// as "app config set".
+type SpecRegistry struct {
+	// Handlers run the commands.
+	Handlers map[string]func(cmd *Command, args []string) error
+	// Args validate the positional arguments of the commands whose validator is
+	// "custom" or "MatchAll", which cannot be created from the spec.
+	Args map[string]PositionalArgs
+	// Completions complete the positional arguments of the commands whose arguments are dynamic.
+	Completions map[string]func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective)
+}
+
+// LoadSpec builds a command tree from a YAML or JSON spec read from r, and binds its
+// commands to the functions of registry. The spec has the format of the output of
+// Describe, so that the spec of an existing tree can be generated with it; the fields
+// derived from others, such as the path or the inherited flags, are ignored. The
+// default help command and the flags added by Cobra are left out, as Cobra adds them
+// to the tree again.
+func LoadSpec(r io.Reader, registry *SpecRegistry) (*Command, error) {
+	var spec TreeDescription
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	return NewCommandFromDescription(&spec, registry)
+}
+
+// NewCommandFromDescription builds a command tree from its description, see LoadSpec.
+func NewCommandFromDescription(spec *TreeDescription, registry *SpecRegistry) (*Command, error) {
+	if spec.SchemaVersion > DescriptionSchemaVersion {
+		return nil, fmt.Errorf("unsupported spec schema version %d, the latest is %d", spec.SchemaVersion, DescriptionSchemaVersion)
+	}
+	if registry == nil {
+		registry = &SpecRegistry{}
+	}
+	return newCommandFromDescription(nil, &spec.Command, registry)
+}
+
+func newCommandFromDescription(parent *Command, d *CommandDescription, registry *SpecRegistry) (*Command, error) {
+	c := &Command{
+		Use:         d.Use,
+		Aliases:     d.Aliases,
+		SuggestFor:  d.SuggestFor,
+		Short:       d.Short,
+		Long:        d.Long,
+		Example:     d.Example,
+		GroupID:     d.GroupID,
+		Version:     d.Version,
+		Deprecated:  d.Deprecated,
+		Hidden:      d.Hidden,
+		Annotations: d.Annotations,
+		ValidArgs:   d.Args.ValidArgs,
+		ArgAliases:  d.Args.ArgAliases,
+	}
+	if c.Use == "" {
+		c.Use = d.Name
+	}
+	if c.Use == "" {
+		return nil, fmt.Errorf("invalid spec: a command has no use line")
+	}
+	for _, g := range d.Groups {
+		c.AddGroup(&Group{ID: g.ID, Title: g.Title})
+	}
+	if parent != nil {
+		parent.AddCommand(c)
+	}
+
+	handler := d.Handler
+	if handler == "" {
+		handler = c.CommandPath()
+	}
+	if run, ok := registry.Handlers[handler]; ok {
+		c.RunE = run
+	} else if d.Runnable {
+		return nil, fmt.Errorf("no handler %q for the runnable command %q", handler, c.CommandPath())
+	}
+	if d.Args.Dynamic {
+		complete, ok := registry.Completions[handler]
+		if !ok {
+			return nil, fmt.Errorf("no completion function %q for the dynamic arguments of %q", handler, c.CommandPath())
+		}
+		c.ValidArgsFunction = complete
+	}
+	args, err := specArgs(&d.Args, registry.Args[handler])
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments of %q: %w", c.CommandPath(), err)
+	}
+	c.Args = args
+
+	for i := range d.Flags {
+		if err := addSpecFlag(c, &d.Flags[i]); err != nil {
+			return nil, fmt.Errorf("invalid flag --%s of %q: %w", d.Flags[i].Name, c.CommandPath(), err)
+		}
+	}
+
+	for i := range d.Commands {
+		sub := &d.Commands[i]
+		if isDefaultCommandDescription(sub) {
+			continue
+		}
+		if _, err := newCommandFromDescription(c, sub, registry); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// isDefaultCommandDescription reports whether d describes the help or completion
+// command added by Cobra, which Execute adds again to the loaded tree.
+func isDefaultCommandDescription(d *CommandDescription) bool {
+	if d.Handler != "" {
+		return false
+	}
+	if d.Name == "help" && d.Use == "help [command]" {
+		return true
+	}
+	if d.Name != compCmdName || d.Use != compCmdName || d.Runnable || len(d.Commands) != 4 {
+		return false
+	}
+	for _, sub := range d.Commands {
+		if !stringInSlice(sub.Name, []string{"bash", "fish", "powershell", "zsh"}) || sub.Handler != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// specArgs returns the validator of the positional arguments described by d.
+// custom is the validator of the registry for the command, if any.
+func specArgs(d *ArgsDescription, custom PositionalArgs) (PositionalArgs, error) {
+	if custom != nil {
+		return custom, nil
+	}
+	switch d.Validator {
+	case "":
+		return nil, nil
+	case "NoArgs":
+		return NoArgs, nil
+	case "ArbitraryArgs":
+		return ArbitraryArgs, nil
+	case "OnlyValidArgs":
+		return OnlyValidArgs, nil
+	case "ExactArgs":
+		if d.Max != nil {
+			return ExactArgs(*d.Max), nil
+		}
+		return ExactArgs(intValue(d.Min)), nil
+	case "MinimumNArgs":
+		return MinimumNArgs(intValue(d.Min)), nil
+	case "MaximumNArgs":
+		return MaximumNArgs(intValue(d.Max)), nil
+	case "RangeArgs":
+		return RangeArgs(intValue(d.Min), intValue(d.Max)), nil
+	case "MatchAll", "custom":
+		return nil, fmt.Errorf("the %s validator must be given by the registry", d.Validator)
+	default:
+		return nil, fmt.Errorf("unknown validator %q", d.Validator)
+	}
+}
+
+// intValue returns the value of n, or 0 if n is nil.
+func intValue(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// specFlagTypes create a flag of each type, by the name of the type.
+var specFlagTypes = map[string]func(flags *flag.FlagSet, name, shorthand, usage string){
+	"string":         func(fs *flag.FlagSet, n, s, u string) { fs.StringP(n, s, "", u) },
+	"bool":           func(fs *flag.FlagSet, n, s, u string) { fs.BoolP(n, s, false, u) },
+	"int":            func(fs *flag.FlagSet, n, s, u string) { fs.IntP(n, s, 0, u) },
+	"int32":          func(fs *flag.FlagSet, n, s, u string) { fs.Int32P(n, s, 0, u) },
+	"int64":          func(fs *flag.FlagSet, n, s, u string) { fs.Int64P(n, s, 0, u) },
+	"uint":           func(fs *flag.FlagSet, n, s, u string) { fs.UintP(n, s, 0, u) },
+	"uint64":         func(fs *flag.FlagSet, n, s, u string) { fs.Uint64P(n, s, 0, u) },
+	"float32":        func(fs *flag.FlagSet, n, s, u string) { fs.Float32P(n, s, 0, u) },
+	"float64":        func(fs *flag.FlagSet, n, s, u string) { fs.Float64P(n, s, 0, u) },
+	"duration":       func(fs *flag.FlagSet, n, s, u string) { fs.DurationP(n, s, 0, u) },
+	"count":          func(fs *flag.FlagSet, n, s, u string) { fs.CountP(n, s, u) },
+	"stringSlice":    func(fs *flag.FlagSet, n, s, u string) { fs.StringSliceP(n, s, nil, u) },
+	"stringArray":    func(fs *flag.FlagSet, n, s, u string) { fs.StringArrayP(n, s, nil, u) },
+	"intSlice":       func(fs *flag.FlagSet, n, s, u string) { fs.IntSliceP(n, s, nil, u) },
+	"boolSlice":      func(fs *flag.FlagSet, n, s, u string) { fs.BoolSliceP(n, s, nil, u) },
+	"float64Slice":   func(fs *flag.FlagSet, n, s, u string) { fs.Float64SliceP(n, s, nil, u) },
+	"durationSlice":  func(fs *flag.FlagSet, n, s, u string) { fs.DurationSliceP(n, s, nil, u) },
+	"stringToString": func(fs *flag.FlagSet, n, s, u string) { fs.StringToStringP(n, s, nil, u) },
+	"stringToInt":    func(fs *flag.FlagSet, n, s, u string) { fs.StringToIntP(n, s, nil, u) },
+}
+
+// addSpecFlag adds the flag described by d to c.
+func addSpecFlag(c *Command, d *FlagDescription) error {
+	if _, ok := d.Annotations[FlagSetByCobraAnnotation]; ok {
+		return nil
+	}
+	create, ok := specFlagTypes[d.Type]
+	if !ok {
+		return fmt.Errorf("unsupported type %q", d.Type)
+	}
+	flags := c.Flags()
+	if d.Persistent {
+		flags = c.PersistentFlags()
+	}
+	create(flags, d.Name, d.Shorthand, d.Usage)
+
+	f := flags.Lookup(d.Name)
+	if err := setSpecDefault(f, d.Default); err != nil {
+		return err
+	}
+	if d.NoOptDefault != "" {
+		f.NoOptDefVal = d.NoOptDefault
+	}
+	f.Hidden = d.Hidden
+	f.Deprecated = d.Deprecated
+	f.ShorthandDeprecated = d.ShorthandDeprecated
+	for key, values := range d.Annotations {
+		if err := flags.SetAnnotation(d.Name, key, values); err != nil {
+			return err
+		}
+	}
+	if d.Required {
+		if err := MarkFlagRequired(flags, d.Name); err != nil {
+			return err
+		}
+	}
+	if d.Negatable {
+		return MarkFlagNegatable(flags, d.Name)
+	}
+	return nil
+}
+
+// setSpecDefault sets the default value of f, as shown by its DefValue.
+func setSpecDefault(f *flag.Flag, def string) error {
+	if def == "" || def == f.DefValue {
+		return nil
+	}
+	slice, isSlice := f.Value.(flag.SliceValue)
+	if isSlice || strings.HasPrefix(f.Value.Type(), "stringTo") {
+		def = strings.TrimSuffix(strings.TrimPrefix(def, "["), "]")
+	}
+	if isSlice {
+		// Setting a slice would make the values given on the command line
+		// append to the default rather than replace it
+		tmp := flag.NewFlagSet("", flag.ContinueOnError)
+		specFlagTypes[f.Value.Type()](tmp, f.Name, "", "")
+		if err := tmp.Set(f.Name, def); err != nil {
+			return err
+		}
+		if err := slice.Replace(tmp.Lookup(f.Name).Value.(flag.SliceValue).GetSlice()); err != nil {
+			return err
+		}
+	} else if err := f.Value.Set(def); err != nil {
+		return err
+	}
+	f.DefValue = f.Value.String()
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func noopHandler(*Command, []string) error { return nil }
+
+func specTestTree() (*Command, *SpecRegistry) {
+	rootCmd := &Command{Use: "app", Short: "The app", Version: "1.0"}
+	rootCmd.PersistentFlags().CountP("verbose", "v", "verbosity")
+	rootCmd.AddGroup(&Group{ID: "manage", Title: "Manage:"})
+
+	getCmd := &Command{
+		Use:        "get <kind> [name]",
+		Aliases:    []string{"g"},
+		SuggestFor: []string{"fetch"},
+		Short:      "Get resources",
+		Long:       "Get one or more resources.",
+		Example:    "app get users",
+		GroupID:    "manage",
+		Args:       RangeArgs(1, 2),
+		ValidArgs:  []string{"users", "groups"},
+		RunE:       noopHandler,
+	}
+	getCmd.Flags().StringP("output", "o", "table", "output format")
+	getCmd.Flags().Bool("json", false, "JSON output")
+	getCmd.Flags().Bool("yaml", false, "YAML output")
+	getCmd.Flags().Bool("cache", true, "use the cache")
+	getCmd.Flags().StringSlice("label", []string{"a", "b"}, "labels")
+	getCmd.Flags().Duration("timeout", 0, "timeout")
+	getCmd.Flags().StringToString("header", map[string]string{"k": "v"}, "headers")
+	getCmd.Flags().Int("limit", 10, "limit")
+	_ = getCmd.Flags().MarkHidden("limit")
+	_ = getCmd.MarkFlagRequired("output")
+	_ = getCmd.MarkFlagNegatable("cache")
+	getCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+
+	configCmd := &Command{Use: "config", Short: "Manage the configuration", Annotations: map[string]string{"area": "config"}}
+	setCmd := &Command{Use: "set <key> <value>", Args: ExactArgs(2), RunE: noopHandler}
+	editCmd := &Command{Use: "edit", Args: NoArgs, Hidden: true, Deprecated: "use set", RunE: noopHandler}
+	completeCmd := &Command{Use: "complete", Args: MinimumNArgs(1), ValidArgsFunction: NoFileCompletions, RunE: noopHandler}
+	configCmd.AddCommand(setCmd, editCmd, completeCmd)
+	rootCmd.AddCommand(getCmd, configCmd)
+
+	registry := &SpecRegistry{
+		Handlers: map[string]func(*Command, []string) error{
+			"app get":             noopHandler,
+			"app config set":      noopHandler,
+			"app config edit":     noopHandler,
+			"app config complete": noopHandler,
+		},
+		Completions: map[string]func(*Command, []string, string) ([]string, ShellCompDirective){
+			"app config complete": NoFileCompletions,
+		},
+	}
+	return rootCmd, registry
+}
+
+func TestLoadSpecRoundTripYAML(t *testing.T) {
+	rootCmd, registry := specTestTree()
+	expected := rootCmd.Describe()
+
+	spec, err := yaml.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSpec(bytes.NewReader(spec), registry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := loaded.Describe(); !reflect.DeepEqual(got, expected) {
+		gotSpec, _ := yaml.Marshal(got)
+		t.Errorf("Expected:\n%s\nGot:\n%s", spec, gotSpec)
+	}
+}
+
+func TestLoadSpecRoundTripAfterExecute(t *testing.T) {
+	rootCmd, registry := specTestTree()
+	if _, err := executeCommand(rootCmd, "--help"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := rootCmd.Describe()
+
+	spec, err := yaml.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSpec(bytes.NewReader(spec), registry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, c := range loaded.Commands() {
+		if c.Name() == compCmdName {
+			t.Errorf("Expected the default completion command not to be loaded")
+		}
+	}
+	if _, err := executeCommand(loaded, "--help"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := loaded.Describe(); !reflect.DeepEqual(got, expected) {
+		gotSpec, _ := yaml.Marshal(got)
+		t.Errorf("Expected:\n%s\nGot:\n%s", spec, gotSpec)
+	}
+}
+
+func TestLoadSpecRoundTripJSON(t *testing.T) {
+	rootCmd, registry := specTestTree()
+	expected := rootCmd.Describe()
+
+	spec, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSpec(bytes.NewReader(spec), registry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := loaded.Describe(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the loaded tree to be described as the original one")
+	}
+}
+
+const specTestYAML = `
+schema_version: 1
+command:
+  use: app
+  short: The app
+  commands:
+    - use: greet <name>
+      short: Greet someone
+      handler: greet
+      args:
+        validator: ExactArgs
+        max: 1
+      flags:
+        - name: greeting
+          shorthand: g
+          type: string
+          default: Hello
+          usage: the greeting
+        - name: tag
+          type: stringSlice
+          default: "[a,b]"
+        - name: loud
+          type: bool
+          negatable: true
+`
+
+func TestLoadSpecHandlers(t *testing.T) {
+	var got []string
+	registry := &SpecRegistry{Handlers: map[string]func(*Command, []string) error{
+		"greet": func(cmd *Command, args []string) error {
+			greeting, _ := cmd.Flags().GetString("greeting")
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			loud, _ := cmd.Flags().GetBool("loud")
+			got = append([]string{greeting, args[0], strings.Join(tags, "+")}, map[bool]string{true: "loud", false: "quiet"}[loud])
+			return nil
+		},
+	}}
+
+	rootCmd, err := LoadSpec(strings.NewReader(specTestYAML), registry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(rootCmd, "greet", "bob", "--loud"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"Hello", "bob", "a+b", "loud"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if _, err := executeCommand(rootCmd, "greet", "bob", "-g", "Hi", "--tag", "c", "--no-loud"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"Hi", "bob", "c", "quiet"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if _, err := executeCommand(rootCmd, "greet"); err == nil || err.Error() != "accepts 1 arg(s), received 0" {
+		t.Errorf("Expected the arguments to be validated, got %v", err)
+	}
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected string
+	}{
+		{name: "Unknown field", spec: "command: {use: app, shrt: typo}", expected: "field shrt not found"},
+		{name: "Newer schema", spec: "schema_version: 99\ncommand: {use: app}", expected: "unsupported spec schema version 99"},
+		{name: "No use line", spec: "command: {short: app}", expected: "a command has no use line"},
+		{name: "Missing handler", spec: "command: {use: app, runnable: true}", expected: `no handler "app" for the runnable command "app"`},
+		{name: "Missing completion", spec: "command: {use: app, args: {dynamic: true}}", expected: `no completion function "app"`},
+		{name: "Custom validator", spec: "command: {use: app, args: {validator: custom}}", expected: "the custom validator must be given by the registry"},
+		{name: "Unknown validator", spec: "command: {use: app, args: {validator: TwoArgs}}", expected: `unknown validator "TwoArgs"`},
+		{name: "Unknown flag type", spec: "command: {use: app, flags: [{name: ip, type: ipNet}]}", expected: `invalid flag --ip of "app": unsupported type "ipNet"`},
+		{name: "Invalid default", spec: "command: {use: app, flags: [{name: n, type: int, default: x}]}", expected: `invalid flag --n of "app"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpec(strings.NewReader(tt.spec), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadSpecRegistryArgs(t *testing.T) {
+	registry := &SpecRegistry{Args: map[string]PositionalArgs{"app": MatchAll(NoArgs)}}
+	rootCmd, err := LoadSpec(strings.NewReader("command: {use: app, args: {validator: MatchAll}}"), registry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name := argsValidatorName(rootCmd.Args); name != "MatchAll" {
+		t.Errorf("Expected the validator of the registry, got %q", name)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// TerminalInfo describes the terminals the streams of a command are connected to.
+// It replaces the detection of the terminals, for example in tests.
+type TerminalInfo struct {
+	// In, Out and Err report whether the input, output and error output are terminals.
+	In, Out, Err bool
+	// Width and Height are the size of the output terminal.
+	// If zero, the size is determined as if the output was not a terminal.
+	Width, Height int
+}
+
+// Streams holds the input and output streams of a command together with the
+// capabilities of the terminals they are connected to.
+type Streams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+
+	// Terminal describes the terminals of the streams instead of detecting them.
+	Terminal *TerminalInfo
+
+	// render is the output Out and Err are rendered for while they are a buffer.
+	render io.Writer
+}
+
+// SetStreams sets the input and output streams of the command.
+// Nil fields are left unchanged. If s.Terminal is set, it describes the
+// terminals of the streams of the command and its children; otherwise the
+// description set before, if any, is kept.
+func (c *Command) SetStreams(s *Streams) {
+	if s.In != nil {
+		c.inReader = s.In
+	}
+	if s.Out != nil {
+		c.outWriter = s.Out
+	}
+	if s.Err != nil {
+		c.errWriter = s.Err
+	}
+	if s.Terminal != nil {
+		c.terminal = s.Terminal
+	}
+}
+
+// SetTerminal sets the description of the terminals of the streams of the command
+// and its children. Nil restores the detection of the terminals, unless a parent
+// of the command describes them.
+func (c *Command) SetTerminal(t *TerminalInfo) {
+	c.terminal = t
+}
+
+// Streams returns the input and output streams of the command.
+func (c *Command) Streams() *Streams {
+	s := &Streams{
+		In:     c.InOrStdin(),
+		Out:    c.OutOrStdout(),
+		Err:    c.ErrOrStderr(),
+		render: c.renderOut,
+	}
+	for p := c; p != nil; p = p.Parent() {
+		if p.terminal != nil {
+			s.Terminal = p.terminal
+			break
+		}
+	}
+	return s
+}
+
+// IsInTerminal reports whether the input is a terminal.
+func (s *Streams) IsInTerminal() bool {
+	if s.Terminal != nil {
+		return s.Terminal.In
+	}
+	return isTerminal(s.In)
+}
+
+// IsOutTerminal reports whether the output is a terminal.
+func (s *Streams) IsOutTerminal() bool {
+	return s.IsTerminal(s.Out)
+}
+
+// IsErrTerminal reports whether the error output is a terminal.
+func (s *Streams) IsErrTerminal() bool {
+	return s.IsTerminal(s.Err)
+}
+
+// IsTerminal reports whether w, the output or error output, is a terminal.
+func (s *Streams) IsTerminal(w io.Writer) bool {
+	if s.Terminal != nil {
+		if sameStream(w, s.Err) && !sameStream(w, s.Out) {
+			return s.Terminal.Err
+		}
+		return s.Terminal.Out
+	}
+	if s.render != nil {
+		w = s.render
+	}
+	return isTerminal(w)
+}
+
+// Interactive reports whether the user can be prompted for input.
+func (s *Streams) Interactive() bool {
+	return s.IsInTerminal()
+}
+
+// Width returns the number of columns of the output.
+// If the output is not a terminal, the COLUMNS environment variable is used
+// and, failing that, a width of 80 columns.
+func (s *Streams) Width() int {
+	if width, _ := s.size(); width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultTerminalWidth
+}
+
+// Height returns the number of lines of the output.
+// If the output is not a terminal, the LINES environment variable is used
+// and, failing that, zero is returned.
+func (s *Streams) Height() int {
+	if _, height := s.size(); height > 0 {
+		return height
+	}
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+	return 0
+}
+
+// size returns the size of the output terminal, or zeros if it is unknown.
+func (s *Streams) size() (width, height int) {
+	if s.Terminal != nil {
+		return s.Terminal.Width, s.Terminal.Height
+	}
+	out := s.Out
+	if s.render != nil {
+		out = s.render
+	}
+	if f, ok := terminalFile(out); ok {
+		if width, height, ok := terminalFileSize(f); ok {
+			return width, height
+		}
+	}
+	return 0, 0
+}
+
+// ColorEnabled reports whether output written to w, the output or error output,
+// can be styled. Styling is disabled when w is not a terminal or when the NO_COLOR
+// environment variable is set. Setting CLICOLOR_FORCE to a value other than "0"
+// enables styling even if w is not a terminal.
+func (s *Streams) ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	return s.IsTerminal(w)
+}
+
+// sameStream reports whether a and b are the same stream.
+func sameStream(a, b interface{}) bool {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,150 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStreamsInherited(t *testing.T) {
+	out := new(bytes.Buffer)
+	terminal := &TerminalInfo{In: true, Out: true, Width: 100, Height: 40}
+	rootCmd := &Command{Use: "root"}
+	childCmd := &Command{Use: "child"}
+	rootCmd.AddCommand(childCmd)
+	rootCmd.SetStreams(&Streams{Out: out, Terminal: terminal})
+
+	streams := childCmd.Streams()
+	if streams.Out != out {
+		t.Errorf("Expected the output of the parent")
+	}
+	if streams.Terminal != terminal {
+		t.Errorf("Expected the terminal of the parent")
+	}
+	if !streams.Interactive() || !streams.IsOutTerminal() || streams.IsErrTerminal() {
+		t.Errorf("Expected the terminals to be described by the parent")
+	}
+	if streams.Width() != 100 || streams.Height() != 40 {
+		t.Errorf("Expected a 100x40 terminal, got %dx%d", streams.Width(), streams.Height())
+	}
+
+	childCmd.SetStreams(&Streams{Terminal: &TerminalInfo{}})
+	if childCmd.Streams().Interactive() {
+		t.Errorf("Expected the terminal of the child to replace the one of the parent")
+	}
+	if childCmd.Streams().Out != out {
+		t.Errorf("Expected nil streams to be left unchanged")
+	}
+
+	rootCmd.SetStreams(&Streams{Out: new(bytes.Buffer)})
+	if rootCmd.Streams().Terminal != terminal {
+		t.Errorf("Expected a nil terminal to leave the terminal unchanged")
+	}
+
+	childCmd.SetTerminal(nil)
+	if childCmd.Streams().Terminal != terminal {
+		t.Errorf("Expected the terminal of the parent once the one of the child is removed")
+	}
+	rootCmd.SetTerminal(nil)
+	if childCmd.Streams().Terminal != nil {
+		t.Errorf("Expected the terminals to be detected once no command describes them")
+	}
+}
+
+func TestStreamsNotTerminal(t *testing.T) {
+	for _, name := range []string{"COLUMNS", "LINES"} {
+		if value, ok := os.LookupEnv(name); ok {
+			os.Unsetenv(name)
+			defer os.Setenv(name, value)
+		}
+	}
+
+	c := &Command{Use: "c"}
+	c.SetStreams(&Streams{In: strings.NewReader(""), Out: new(bytes.Buffer), Err: new(bytes.Buffer)})
+
+	streams := c.Streams()
+	if streams.Interactive() || streams.IsOutTerminal() || streams.IsErrTerminal() {
+		t.Errorf("Expected buffers not to be terminals")
+	}
+	if streams.Width() != defaultTerminalWidth || streams.Height() != 0 {
+		t.Errorf("Expected the default size, got %dx%d", streams.Width(), streams.Height())
+	}
+
+	os.Setenv("COLUMNS", "120")
+	defer os.Unsetenv("COLUMNS")
+	os.Setenv("LINES", "30")
+	defer os.Unsetenv("LINES")
+	if streams.Width() != 120 || streams.Height() != 30 {
+		t.Errorf("Expected the size from the environment, got %dx%d", streams.Width(), streams.Height())
+	}
+}
+
+func TestStreamsColorEnabled(t *testing.T) {
+	os.Unsetenv("NO_COLOR")
+	os.Unsetenv("CLICOLOR_FORCE")
+
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	c := &Command{Use: "c"}
+	c.SetStreams(&Streams{Out: out, Err: errOut, Terminal: &TerminalInfo{Out: true}})
+
+	streams := c.Streams()
+	if !streams.ColorEnabled(out) {
+		t.Errorf("Expected color on the output terminal")
+	}
+	if streams.ColorEnabled(errOut) {
+		t.Errorf("Expected no color on the error output")
+	}
+
+	os.Setenv("CLICOLOR_FORCE", "1")
+	if !streams.ColorEnabled(errOut) {
+		t.Errorf("Expected CLICOLOR_FORCE to enable color")
+	}
+	os.Unsetenv("CLICOLOR_FORCE")
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	if streams.ColorEnabled(out) {
+		t.Errorf("Expected NO_COLOR to disable color")
+	}
+}
+
+func TestHelpWidthFromStreams(t *testing.T) {
+	defer func(wrapping bool) { EnableHelpWrapping = wrapping }(EnableHelpWrapping)
+	EnableHelpWrapping = true
+
+	c := &Command{Use: "c"}
+	c.SetStreams(&Streams{Terminal: &TerminalInfo{Out: true, Width: 42}})
+	if width := c.HelpWidth(); width != 42 {
+		t.Errorf("Expected a help width of 42, got %d", width)
+	}
+}
+
+func TestThemeOnTerminalStreams(t *testing.T) {
+	os.Unsetenv("NO_COLOR")
+	os.Unsetenv("CLICOLOR_FORCE")
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.SetTheme(&Theme{Heading: "1"})
+	rootCmd.SetStreams(&Streams{Terminal: &TerminalInfo{Out: true}})
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "\x1b[1mUsage:\x1b[0m")
+}
@@ -0,0 +1,35 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"os"
+)
+
+// defaultTerminalWidth is the width assumed for output that is not a
+// terminal when the COLUMNS environment variable is not set either.
+const defaultTerminalWidth = 80
+
+// terminalFile returns the *os.File behind v, if any.
+func terminalFile(v interface{}) (*os.File, bool) {
+	f, ok := v.(*os.File)
+	return f, ok && f != nil
+}
+
+// isTerminal reports whether v is a file connected to a terminal.
+func isTerminal(v interface{}) bool {
+	f, ok := terminalFile(v)
+	return ok && isTerminalFile(f)
+}
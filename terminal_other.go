@@ -0,0 +1,44 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package cobra
+
+import (
+	"errors"
+	"os"
+)
+
+// terminalFileSize returns the width and height of the terminal f is connected to.
+// The size of a terminal cannot be queried on this platform, so ok is always false.
+func terminalFileSize(f *os.File) (width, height int, ok bool) {
+	return 0, 0, false
+}
+
+// isTerminalFile reports whether f is connected to a terminal.
+func isTerminalFile(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// disableEcho stops the terminal f from echoing the input typed in it.
+// This is not supported on this platform, so an error is always returned.
+func disableEcho(f *os.File) (restore func(), err error) {
+	return nil, errors.New("cannot disable the echo of the terminal on this platform")
+}
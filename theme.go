@@ -0,0 +1,152 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Theme defines how help, usage and error output is styled when it is written to a terminal.
+// Each field holds the ANSI SGR parameters applied to an element of the output, for example
+// "1" for bold or "1;36" for bold cyan. Elements with an empty field are not styled.
+type Theme struct {
+	// Heading styles section headings such as "Usage:" and group titles.
+	Heading string
+	// Command styles the names of subcommands and help topics.
+	Command string
+	// Flag styles flag names and shorthands.
+	Flag string
+	// Placeholder styles the value placeholders of flags.
+	Placeholder string
+	// ErrPrefix styles the error message prefix.
+	ErrPrefix string
+}
+
+// DefaultTheme returns the theme Cobra suggests for colored output.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Heading:     "1",
+		Command:     "36",
+		Flag:        "33",
+		Placeholder: "3",
+		ErrPrefix:   "1;31",
+	}
+}
+
+// SetTheme sets the theme used to style help, usage and error output.
+// A nil theme disables styling.
+func (c *Command) SetTheme(theme *Theme) {
+	c.theme = theme
+}
+
+// Theme returns the theme set by SetTheme for this command or a parent,
+// or nil if output is not styled.
+func (c *Command) Theme() *Theme {
+	if c.theme != nil {
+		return c.theme
+	}
+	if c.HasParent() {
+		return c.parent.Theme()
+	}
+	return nil
+}
+
+// colorEnabled reports whether output written to w should be styled.
+// Styling requires a theme and streams that can be styled, see Streams.ColorEnabled.
+func (c *Command) colorEnabled(w io.Writer) bool {
+	return c.Theme() != nil && c.Streams().ColorEnabled(w)
+}
+
+// activeTheme returns the theme to apply to output written to w.
+// The returned theme is empty if the output should not be styled.
+func (c *Command) activeTheme(w io.Writer) *Theme {
+	if !c.colorEnabled(w) {
+		return &Theme{}
+	}
+	return c.Theme()
+}
+
+// styleFuncs returns the template functions styling output written to w.
+func (c *Command) styleFuncs(w io.Writer) template.FuncMap {
+	theme := c.activeTheme(w)
+	return template.FuncMap{
+		"styleHeading":     func(s string) string { return sgr(theme.Heading, s) },
+		"styleCommand":     func(s string) string { return sgr(theme.Command, s) },
+		"styleFlag":        func(s string) string { return sgr(theme.Flag, s) },
+		"stylePlaceholder": func(s string) string { return sgr(theme.Placeholder, s) },
+		"styleErrPrefix":   func(s string) string { return sgr(theme.ErrPrefix, s) },
+		"styleFlagUsages":  func(s string) string { return styleFlagUsages(theme, s) },
+	}
+}
+
+// styledErrPrefix returns the error message prefix, styled for output written to w.
+func (c *Command) styledErrPrefix(w io.Writer) string {
+	return sgr(c.activeTheme(w).ErrPrefix, c.ErrPrefix())
+}
+
+// sgr wraps s in the ANSI escape sequences applying the SGR parameters params.
+func sgr(params, s string) string {
+	if params == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + params + "m" + s + "\x1b[0m"
+}
+
+var sgrRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripSGR removes the ANSI SGR escape sequences from s.
+func stripSGR(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	return sgrRegexp.ReplaceAllString(s, "")
+}
+
+// flagUsageRegexp matches the beginning of a flag line produced by FlagUsages:
+// the optional shorthand, the flag name, possibly negatable, and the optional value placeholder.
+var flagUsageRegexp = regexp.MustCompile(`^  (?:(-[^-\s]), |    )(--(?:\[no-\])?[^\s\[]+)(?: ([^\s]+))?`)
+
+// styleFlagUsages styles the flag names and placeholders of usages, as produced by FlagUsages.
+func styleFlagUsages(theme *Theme, usages string) string {
+	if theme.Flag == "" && theme.Placeholder == "" {
+		return usages
+	}
+	lines := strings.Split(usages, "\n")
+	for i, line := range lines {
+		m := flagUsageRegexp.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		var sb strings.Builder
+		sb.WriteString("  ")
+		if m[2] >= 0 {
+			sb.WriteString(sgr(theme.Flag, line[m[2]:m[3]]))
+			sb.WriteString(", ")
+		} else {
+			sb.WriteString("    ")
+		}
+		sb.WriteString(sgr(theme.Flag, line[m[4]:m[5]]))
+		if m[6] >= 0 {
+			sb.WriteString(" ")
+			sb.WriteString(sgr(theme.Placeholder, line[m[6]:m[7]]))
+		}
+		sb.WriteString(line[m[1]:])
+		lines[i] = sb.String()
+	}
+	return strings.Join(lines, "\n")
+}
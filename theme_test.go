@@ -0,0 +1,122 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func setupThemeTest() *Command {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Short: "child command", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+	rootCmd.Flags().StringP("name", "n", "", "the name")
+	rootCmd.SetTheme(&Theme{Heading: "1", Command: "36", Flag: "33", Placeholder: "3", ErrPrefix: "31"})
+	return rootCmd
+}
+
+func TestThemeNotAppliedWithoutTerminal(t *testing.T) {
+	rootCmd := setupThemeTest()
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringOmits(t, output, "\x1b[")
+	checkStringContains(t, output, "Usage:")
+}
+
+func TestThemeAppliedWhenForced(t *testing.T) {
+	os.Setenv("CLICOLOR_FORCE", "1")
+	defer os.Unsetenv("CLICOLOR_FORCE")
+	rootCmd := setupThemeTest()
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringContains(t, output, "\x1b[1mUsage:\x1b[0m")
+	checkStringContains(t, output, "\x1b[1mAvailable Commands:\x1b[0m")
+	checkStringContains(t, output, "  \x1b[36mchild      \x1b[0m child command")
+	checkStringContains(t, output, "  \x1b[33m-n\x1b[0m, \x1b[33m--name\x1b[0m \x1b[3mstring\x1b[0m   the name")
+	checkStringContains(t, output, "  \x1b[33m-h\x1b[0m, \x1b[33m--help\x1b[0m          help for root")
+}
+
+func TestThemeDisabledByNoColor(t *testing.T) {
+	os.Setenv("CLICOLOR_FORCE", "1")
+	defer os.Unsetenv("CLICOLOR_FORCE")
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	rootCmd := setupThemeTest()
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringOmits(t, output, "\x1b[")
+}
+
+func TestThemeErrPrefix(t *testing.T) {
+	os.Setenv("CLICOLOR_FORCE", "1")
+	defer os.Unsetenv("CLICOLOR_FORCE")
+	rootCmd := setupThemeTest()
+	rootCmd.SilenceUsage = true
+
+	output, err := executeCommand(rootCmd, "--unknown")
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+
+	checkStringContains(t, output, "\x1b[31mError:\x1b[0m unknown flag: --unknown")
+}
+
+func TestThemeInherited(t *testing.T) {
+	rootCmd := setupThemeTest()
+	childCmd := rootCmd.Commands()[0]
+
+	if childCmd.Theme() != rootCmd.Theme() {
+		t.Errorf("Expected child to inherit the theme of its parent")
+	}
+
+	rootCmd.SetTheme(nil)
+	if childCmd.Theme() != nil {
+		t.Errorf("Expected no theme, got %v", childCmd.Theme())
+	}
+}
+
+func TestThemeKeepsWrappingAligned(t *testing.T) {
+	os.Setenv("CLICOLOR_FORCE", "1")
+	defer os.Unsetenv("CLICOLOR_FORCE")
+	rootCmd := setupThemeTest()
+	rootCmd.Commands()[0].Short = "a child command with a description long enough to wrap"
+	rootCmd.SetHelpWidth(50)
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(stripSGR(output), "\n") {
+		if strings.HasPrefix(line, "  child") && len(line) > 50 {
+			t.Errorf("Expected styled lines to be wrapped at 50 columns, got %q", line)
+		}
+	}
+	checkStringContains(t, stripSGR(output), "  child       a child command with a description\n              long enough to wrap\n")
+}
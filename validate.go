@@ -0,0 +1,197 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Validate checks the definition of the command and all its subcommands and
+// returns the mistakes found:
+//   - subcommands sharing a name or an alias with a sibling, ignoring case if
+//     EnableCaseInsensitive is set,
+//   - flags whose shorthand is already used by an inherited persistent flag,
+//   - commands setting both ValidArgs and ValidArgsFunction,
+//   - subcommands whose GroupID was not added to their parent with AddGroup,
+//   - flag groups referring to flags that a command does not have,
+//   - Args validators built by Cobra, such as ExactArgs, rejecting the number of
+//     arguments named by Use,
+//   - commands that are neither runnable, nor have subcommands or a Long help text.
+//
+// Validate does not execute the commands. It is meant to be called from a unit test
+// so that these mistakes are reported before users run into them.
+func (c *Command) Validate() []error {
+	var errs []error
+	errs = append(errs, c.validateSubcommandNames()...)
+	errs = append(errs, c.validateFlagShorthands()...)
+	errs = append(errs, c.validateFlagGroups()...)
+	errs = append(errs, c.validateArgs()...)
+	if len(c.ValidArgs) > 0 && c.ValidArgsFunction != nil {
+		errs = append(errs, c.validationError("both ValidArgs and ValidArgsFunction are set"))
+	}
+	if !c.Runnable() && !c.HasSubCommands() && c.Long == "" {
+		errs = append(errs, c.validationError("command is not runnable and has no subcommands"))
+	}
+	for _, sub := range c.commands {
+		errs = append(errs, sub.Validate()...)
+	}
+	return errs
+}
+
+// validationError returns an error about the definition of c.
+func (c *Command) validationError(format string, a ...interface{}) error {
+	return fmt.Errorf("%s: %s", c.CommandPath(), fmt.Sprintf(format, a...))
+}
+
+// validateSubcommandNames reports the subcommands of c sharing a name or an
+// alias with a sibling, ignoring case if EnableCaseInsensitive is set, and the
+// subcommands in an undefined group.
+func (c *Command) validateSubcommandNames() []error {
+	var errs []error
+	seen := map[string]*Command{}
+	for _, sub := range c.commands {
+		for _, name := range append([]string{sub.Name()}, sub.Aliases...) {
+			key := name
+			if EnableCaseInsensitive {
+				key = strings.ToLower(name)
+			}
+			if other, ok := seen[key]; ok && other != sub {
+				errs = append(errs, c.validationError("subcommands %q and %q both use the name %q", other.Name(), sub.Name(), name))
+				continue
+			}
+			seen[key] = sub
+		}
+		if sub.GroupID != "" && !c.ContainsGroup(sub.GroupID) {
+			errs = append(errs, sub.validationError("group id %q is not defined on %q", sub.GroupID, c.CommandPath()))
+		}
+	}
+	return errs
+}
+
+// inheritedPersistentFlags returns the persistent flags c inherits from its parents,
+// by name, without merging them into the flags of c.
+func (c *Command) inheritedPersistentFlags() map[string]*flag.Flag {
+	inherited := map[string]*flag.Flag{}
+	for p := c.parent; p != nil; p = p.parent {
+		p.PersistentFlags().VisitAll(func(f *flag.Flag) {
+			if _, ok := inherited[f.Name]; !ok {
+				inherited[f.Name] = f
+			}
+		})
+	}
+	return inherited
+}
+
+// ownFlags returns the local and persistent flags defined on c itself.
+func (c *Command) ownFlags(inherited map[string]*flag.Flag) []*flag.Flag {
+	var own []*flag.Flag
+	seen := map[*flag.Flag]bool{}
+	add := func(f *flag.Flag) {
+		if !seen[f] && inherited[f.Name] != f {
+			seen[f] = true
+			own = append(own, f)
+		}
+	}
+	c.PersistentFlags().VisitAll(add)
+	c.Flags().VisitAll(add)
+	return own
+}
+
+// validateFlagShorthands reports the flags of c whose shorthand is used by a
+// different inherited persistent flag.
+func (c *Command) validateFlagShorthands() []error {
+	inherited := c.inheritedPersistentFlags()
+	byShorthand := map[string]*flag.Flag{}
+	for _, f := range inherited {
+		if f.Shorthand != "" {
+			byShorthand[f.Shorthand] = f
+		}
+	}
+
+	var errs []error
+	for _, f := range c.ownFlags(inherited) {
+		if other, ok := byShorthand[f.Shorthand]; ok && f.Shorthand != "" && other.Name != f.Name {
+			errs = append(errs, c.validationError("shorthand -%s of flag --%s is already used by the inherited flag --%s", f.Shorthand, f.Name, other.Name))
+		}
+	}
+	return errs
+}
+
+// validateFlagGroups reports the flag groups of the flags of c referring to
+// flags c does not have. Such groups are silently ignored when c runs.
+func (c *Command) validateFlagGroups() []error {
+	inherited := c.inheritedPersistentFlags()
+	flags := map[string]*flag.Flag{}
+	for name, f := range inherited {
+		flags[name] = f
+	}
+	for _, f := range c.ownFlags(inherited) {
+		flags[f.Name] = f
+	}
+
+	var groups []string
+	seen := map[string]bool{}
+	for _, f := range flags {
+		for _, annotation := range []string{requiredAsGroupAnnotation, oneRequiredAnnotation, mutuallyExclusiveAnnotation} {
+			for _, group := range f.Annotations[annotation] {
+				if !seen[group] {
+					seen[group] = true
+					groups = append(groups, group)
+				}
+			}
+		}
+	}
+	sort.Strings(groups)
+
+	var errs []error
+	for _, group := range groups {
+		for _, name := range strings.Split(group, " ") {
+			if _, ok := flags[name]; !ok {
+				errs = append(errs, c.validationError("flag group [%s] refers to the missing flag --%s", group, name))
+			}
+		}
+	}
+	return errs
+}
+
+// validateArgs reports a validator built by Cobra, such as ExactArgs or RangeArgs,
+// rejecting the number of arguments named by Use: the required arguments such as
+// "<name>", and one more if Use names optional or repeated arguments such as "[name]"
+// or "<name>...". Other validators are not checked, as checking them would run them.
+func (c *Command) validateArgs() []error {
+	info, ok := lookupArgs(c.Args)
+	if !ok || !info.counted {
+		return nil
+	}
+	n := len(requiredArgNames(c.Use))
+	more := false
+	for i, field := range strings.Fields(c.Use) {
+		if i > 0 && field != "[flags]" && (strings.HasPrefix(field, "[") || strings.HasSuffix(field, "...")) {
+			more = true
+		}
+	}
+
+	var errs []error
+	if n < info.min || (info.max >= 0 && n > info.max) {
+		errs = append(errs, c.validationError("Args rejects the %d argument(s) named by Use %q: %s", n, c.Use, info.countString()))
+	} else if more && info.max >= 0 && n+1 > info.max {
+		errs = append(errs, c.validationError("Args rejects the optional arguments named by Use %q: %s", c.Use, info.countString()))
+	}
+	return errs
+}
@@ -0,0 +1,155 @@
+// Copyright 2013-2023 The Cobra Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func checkValidationErrors(t *testing.T, errs []error, expected ...string) {
+	t.Helper()
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d error(s), got %q", len(expected), errs)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), expected[i]) {
+			t.Errorf("Expected error %d to contain %q, got %q", i, expected[i], err)
+		}
+	}
+}
+
+func TestValidateValidTree(t *testing.T) {
+	rootCmd := &Command{Use: "root", Long: "The root command"}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose")
+	rootCmd.AddGroup(&Group{ID: "main", Title: "Main"})
+	getCmd := &Command{Use: "get <kind> [name]", Args: RangeArgs(1, 2), GroupID: "main", Run: emptyRun}
+	getCmd.Flags().StringP("output", "o", "", "output")
+	getCmd.Flags().Bool("json", false, "json")
+	getCmd.Flags().Bool("yaml", false, "yaml")
+	getCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	listCmd := &Command{Use: "list", Aliases: []string{"ls"}, Args: NoArgs, Run: emptyRun}
+	rootCmd.AddCommand(getCmd, listCmd, &Command{Use: "topic", Long: "A help topic"})
+
+	checkValidationErrors(t, rootCmd.Validate())
+}
+
+func TestValidateDuplicateNames(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(
+		&Command{Use: "list", Aliases: []string{"ls"}, Run: emptyRun},
+		&Command{Use: "ls", Run: emptyRun},
+		&Command{Use: "list", Run: emptyRun},
+	)
+
+	checkValidationErrors(t, rootCmd.Validate(),
+		`root: subcommands "list" and "ls" both use the name "ls"`,
+		`root: subcommands "list" and "list" both use the name "list"`,
+	)
+}
+
+func TestValidateDuplicateNamesCaseInsensitive(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(
+		&Command{Use: "list", Run: emptyRun},
+		&Command{Use: "List", Run: emptyRun},
+	)
+
+	checkValidationErrors(t, rootCmd.Validate())
+
+	defer func() { EnableCaseInsensitive = defaultCaseInsensitive }()
+	EnableCaseInsensitive = true
+	checkValidationErrors(t, rootCmd.Validate(), `root: subcommands "list" and "List" both use the name "List"`)
+}
+
+func TestValidateShorthandCollision(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().BoolP("version", "v", false, "version")
+	childCmd.Flags().BoolP("verbose", "V", false, "shadows the inherited flag")
+	rootCmd.AddCommand(childCmd)
+
+	checkValidationErrors(t, rootCmd.Validate(),
+		"root child: shorthand -v of flag --version is already used by the inherited flag --verbose",
+	)
+}
+
+func TestValidateValidArgs(t *testing.T) {
+	c := &Command{Use: "c", ValidArgs: []string{"a"}, ValidArgsFunction: NoFileCompletions, Run: emptyRun}
+
+	checkValidationErrors(t, c.Validate(), "c: both ValidArgs and ValidArgsFunction are set")
+}
+
+func TestValidateGroupID(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", GroupID: "main", Run: emptyRun})
+
+	checkValidationErrors(t, rootCmd.Validate(), `root child: group id "main" is not defined on "root"`)
+}
+
+func TestValidateFlagGroupsMissingFlag(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().Bool("json", false, "json")
+	rootCmd.Flags().Bool("yaml", false, "yaml")
+	rootCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	checkValidationErrors(t, rootCmd.Validate(), "root child: flag group [json yaml] refers to the missing flag --yaml")
+}
+
+func TestValidateArgs(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(
+		&Command{Use: "delete <name>", Args: NoArgs, Run: emptyRun},
+		&Command{Use: "list", Args: ExactArgs(1), Run: emptyRun},
+		&Command{Use: "get <kind> [name]", Args: ExactArgs(1), Run: emptyRun},
+		&Command{Use: "cp <src>... <dest>", Args: MinimumNArgs(2), Run: emptyRun},
+		&Command{Use: "use <context>", Args: OnlyValidArgs, ValidArgs: []string{"dev", "prod"}, Run: emptyRun},
+		&Command{Use: "set <key> <value>", Args: MatchAll(ExactArgs(1), OnlyValidArgs), Run: emptyRun},
+	)
+
+	checkValidationErrors(t, rootCmd.Validate(),
+		`root delete: Args rejects the 1 argument(s) named by Use "delete <name>": NoArgs accepts 0 argument(s)`,
+		`root list: Args rejects the 0 argument(s) named by Use "list": ExactArgs accepts 1 argument(s)`,
+		`root get: Args rejects the optional arguments named by Use "get <kind> [name]": ExactArgs accepts 1 argument(s)`,
+		`root set: Args rejects the 2 argument(s) named by Use "set <key> <value>": MatchAll accepts 1 argument(s)`,
+	)
+}
+
+func TestValidateArgsDoesNotRunCustomValidators(t *testing.T) {
+	called := false
+	custom := func(cmd *Command, args []string) error {
+		called = true
+		return nil
+	}
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(
+		&Command{Use: "custom <name>", Args: custom, Run: emptyRun},
+		&Command{Use: "all <name>", Args: MatchAll(ExactArgs(0), custom), Run: emptyRun},
+	)
+
+	checkValidationErrors(t, rootCmd.Validate())
+	if called {
+		t.Error("Expected Validate not to call the Args validators")
+	}
+}
+
+func TestValidateNotRunnableLeaf(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	rootCmd.AddCommand(&Command{Use: "todo"}, &Command{Use: "topic", Long: "A help topic"})
+
+	checkValidationErrors(t, rootCmd.Validate(), "root todo: command is not runnable and has no subcommands")
+}